@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"github.com/zchee/color"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Items older than this share of the completed issues are highlighted
+const agingWarningPercentile = 85
+
+type AgingItem struct {
+	Key        string
+	Title      string
+	IssueType  string
+	Status     string
+	StatusType string
+	WipDate    time.Time
+	Age        ReportDuration
+	Flagged    bool
+	// Share of the completed issues of the same type with a shorter cycle time
+	PercentileRank float64
+	Position       string
+}
+
+type Aging struct {
+	Date  time.Time
+	Items []AgingItem
+}
+
+// Lists the issues in a WIP or Idle status at the end of endDate, oldest first, comparing their age
+// with the cycle time of the issues resolved during the period
+func getAging(issueDetails []IssueDetails, endDate time.Time) Aging {
+	asOf := endDate.AddDate(0, 0, 1)
+	aging := Aging{Date: endDate}
+
+	cycleTimesByType := make(map[string][]time.Duration)
+	for _, issueDetails := range issueDetails {
+		if !issueDetails.ResolvedDate.IsZero() {
+			cycleTime := issueDetails.GetWipAndIdleTotalDuration()
+			cycleTimesByType[issueDetails.IssueType] = append(cycleTimesByType[issueDetails.IssueType], cycleTime)
+			cycleTimesByType[allIssueTypes] = append(cycleTimesByType[allIssueTypes], cycleTime)
+		}
+	}
+
+	for _, issueDetails := range issueDetails {
+		if issueDetails.TransitionDetails == nil {
+			continue
+		}
+		status := issueDetails.TransitionDetails.StatusTo
+		statusType := getIssueTypeByStatus(status)
+		if statusType != wipStatusType && statusType != idleStatusType {
+			continue
+		}
+
+		wipDate := getAgingStart(issueDetails)
+		age := getTransitionDuration(wipDate, asOf)
+		item := AgingItem{
+			Key:        issueDetails.Key,
+			Title:      issueDetails.Title,
+			IssueType:  issueDetails.IssueType,
+			Status:     status,
+			StatusType: statusType,
+			WipDate:    wipDate,
+			Age:        newReportDuration(age),
+			Flagged:    issueDetails.IsFlaggedAt(asOf),
+		}
+
+		reference, referenceName := cycleTimesByType[issueDetails.IssueType], issueDetails.IssueType
+		if len(reference) == 0 {
+			reference, referenceName = cycleTimesByType[allIssueTypes], "issues"
+		}
+		item.PercentileRank, item.Position = getAgingPosition(age, sortDurations(reference), referenceName)
+		aging.Items = append(aging.Items, item)
+	}
+
+	sort.SliceStable(aging.Items, func(i, j int) bool {
+		return aging.Items[i].Age.Value > aging.Items[j].Age.Value
+	})
+	return aging
+}
+
+// Age is counted from the WIP date, or from the first move to an Idle status for issues that skipped WIP,
+// or from the created date when the changelog has neither
+func getAgingStart(issueDetails IssueDetails) time.Time {
+	if !issueDetails.WipDate.IsZero() {
+		return issueDetails.WipDate
+	}
+	start := issueDetails.CreatedDate
+	for transition := issueDetails.TransitionDetails; transition != nil; transition = transition.PreviousTransition {
+		if getIssueTypeByStatus(transition.StatusTo) == idleStatusType {
+			start = transition.Timestamp
+		}
+	}
+	return start
+}
+
+// Places the age among the completed cycle times, e.g. "older than 85% of completed Story"
+func getAgingPosition(age time.Duration, cycleTimes []time.Duration, issueType string) (float64, string) {
+	if len(cycleTimes) == 0 {
+		return 0, "no completed issues to compare with"
+	}
+	var shorter int
+	for _, cycleTime := range cycleTimes {
+		if cycleTime < age {
+			shorter++
+		}
+	}
+	rank := roundFloat(float64(shorter*100) / float64(len(cycleTimes)))
+
+	position := fmt.Sprintf("within the %dth percentile of completed %v", distributionPercentiles[0], issueType)
+	for _, percentile := range distributionPercentiles {
+		if age > getPercentile(cycleTimes, percentile) {
+			position = fmt.Sprintf("older than %d%% of completed %v", percentile, issueType)
+		}
+	}
+	return rank, position
+}
+
+const agingJql = "project = '%v'%v AND issuetype != Epic AND (%v OR status CHANGED DURING('%v', '%v')) ORDER BY status"
+
+// The issues of the period plus every issue currently in a WIP or Idle status, which may not have moved for a while
+func getAgingJqlSearch() string {
+	inFlight := "statusCategory = 'In Progress'"
+	statuses := getInFlightJqlStatuses()
+	if len(statuses) > 0 {
+		inFlight = fmt.Sprintf("status IN (%v)", strings.Join(statuses, ", "))
+		if BoardCfg.StatusCategoryFallback {
+			inFlight += " OR statusCategory = 'In Progress'"
+		}
+	}
+	jqlSearch := fmt.Sprintf(agingJql, BoardCfg.Project, getBoardJqlFilter(), inFlight,
+		formatJiraDate(parseDate(CLParameters.StartDate)), formatJiraDate(parseDate(CLParameters.EndDate)))
+	return jqlSearch
+}
+
+// WIP and Idle statuses of the board config as JQL values. Jira rejects the whole query when a status does not exist,
+// so statuses missing from the ones loaded from Jira are left out with a warning.
+func getInFlightJqlStatuses() []string {
+	var statuses []string
+	for _, status := range append(append([]string{}, BoardCfg.WipStatus...), BoardCfg.IdleStatus...) {
+		_, isId := knownStatusesById[status]
+		if len(knownStatusesById) > 0 && !isId && getKnownStatus(status).Id == "" {
+			log.Printf("Warning: status %v of %v does not exist in Jira, it is left out of the aging search", status, configPath)
+			continue
+		}
+		if _, err := strconv.Atoi(status); err == nil {
+			// status ID
+			statuses = append(statuses, status)
+		} else {
+			statuses = append(statuses, fmt.Sprintf("\"%v\"", strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(status)))
+		}
+	}
+	return statuses
+}
+
+// Cached issues whose current status is a WIP or Idle status
+func isInFlight(issue jira.Issue) bool {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return false
+	}
+	statusType := getIssueTypeByStatus(issue.Fields.Status.Name)
+	return statusType == wipStatusType || statusType == idleStatusType
+}
+
+func writeAging(aging Aging) {
+	switch CLParameters.Format {
+	case "text":
+		printAging(aging)
+	case "json":
+		withOutput(func(output io.Writer) {
+			writeJSON(output, aging, "aging")
+		})
+	case "csv":
+		withOutput(func(output io.Writer) {
+			printAgingCSV(output, aging)
+		})
+	default:
+		log.Fatalf("Unknown output format: %v", CLParameters.Format)
+	}
+}
+
+func printAging(aging Aging) {
+	title("Aging work in progress of project %s // ", BoardCfg.Project)
+	title("On %s\n", formatBrDate(aging.Date))
+	if len(aging.Items) == 0 {
+		fmt.Println("No issues in WIP or Idle statuses")
+		return
+	}
+
+	for _, item := range aging.Items {
+		fmt.Printf("%s | %s | %s | %s (%s) | Since: %s | ", color.RedString(item.Key), item.Title, item.IssueType,
+			item.Status, item.StatusType, formatBrDate(item.WipDate))
+		warn("%.1f days", item.Age.Days)
+		if item.Flagged {
+			warn(" | Flagged")
+		}
+		if item.PercentileRank >= agingWarningPercentile {
+			warn(" | %s\n", item.Position)
+		} else {
+			info(" | %s\n", item.Position)
+		}
+	}
+}
+
+func printAgingCSV(output io.Writer, aging Aging) {
+	records := [][]string{{"Key", "Title", "Type", "Status", "Status Type", "WIP Date", "Age Days",
+		"Flagged", "Percentile Rank", "Position"}}
+	for _, item := range aging.Items {
+		records = append(records, []string{
+			item.Key,
+			item.Title,
+			item.IssueType,
+			item.Status,
+			item.StatusType,
+			formatBrDate(item.WipDate),
+			formatCSVFloat(item.Age.Days),
+			fmt.Sprint(item.Flagged),
+			formatCSVFloat(item.PercentileRank),
+			item.Position,
+		})
+	}
+	writeCSV(output, records, "aging")
+}
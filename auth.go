@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"golang.org/x/term"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Supported values of BoardCfg.AuthType
+const (
+	basicAuth  = "basic"
+	bearerAuth = "bearer"
+)
+
+// Environment variable read for the secret when SecretEnv is not configured
+const defaultSecretEnv = "JIRA_API_TOKEN"
+
+// Wraps the transport with basic auth (password or Jira Cloud API token) or bearer auth (personal access token)
+func getAuthClient(transport http.RoundTripper) *http.Client {
+	switch getAuthType() {
+	case basicAuth:
+		login := strings.TrimSpace(BoardCfg.Login)
+		if login == "" {
+			return &http.Client{Transport: transport}
+		}
+		tp := jira.BasicAuthTransport{
+			Username:  login,
+			Password:  getSecret(),
+			Transport: transport,
+		}
+		return tp.Client()
+	case bearerAuth:
+		return &http.Client{Transport: &bearerAuthTransport{Token: getSecret(), Transport: transport}}
+	default:
+		log.Fatalf("Unknown AuthType %v in config file, expected %v or %v", BoardCfg.AuthType, basicAuth, bearerAuth)
+		return nil
+	}
+}
+
+func getAuthType() string {
+	if BoardCfg.AuthType == "" {
+		return basicAuth
+	}
+	return strings.ToLower(BoardCfg.AuthType)
+}
+
+// Returns the password or token from the config file, the secret environment variable,
+// the output of the secret command or an interactive prompt, in this order
+func getSecret() string {
+	if password := strings.TrimSpace(BoardCfg.Password); password != "" {
+		log.Printf("Warning: plaintext Password in %v, consider using SecretEnv or SecretCommand instead", configPath)
+		return password
+	}
+
+	secretEnv := BoardCfg.SecretEnv
+	if secretEnv == "" {
+		secretEnv = defaultSecretEnv
+	}
+	if secret := strings.TrimSpace(os.Getenv(secretEnv)); secret != "" {
+		return secret
+	}
+
+	if BoardCfg.SecretCommand != "" {
+		return runSecretCommand(BoardCfg.SecretCommand)
+	}
+
+	return promptSecret()
+}
+
+func runSecretCommand(command string) string {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		log.Fatalf("Failed to run SecretCommand %q: %v", command, err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func promptSecret() string {
+	stdin := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdin) {
+		log.Fatalf("No Jira secret found, set Password, SecretEnv or SecretCommand in the config file or the %v environment variable", defaultSecretEnv)
+	}
+
+	what := "Password or API token"
+	if getAuthType() == bearerAuth {
+		what = "Personal access token"
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "%v for %v: ", what, BoardCfg.JiraUrl)
+	secret, err := term.ReadPassword(stdin)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		log.Fatalf("Failed to read secret: %v", err)
+	}
+	return strings.TrimSpace(string(secret))
+}
+
+type bearerAuthTransport struct {
+	Token     string
+	Transport http.RoundTripper
+}
+
+func (t *bearerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+t.Token)
+	return t.Transport.RoundTrip(req2)
+}
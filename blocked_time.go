@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"github.com/zchee/color"
+	"sort"
+	"time"
+)
+
+type TypeBlockedTime struct {
+	IssueType string
+	// Issues flagged at least once
+	Issues  int
+	Events  int
+	Blocked ReportDuration
+	// Share of the cycle time of the issues spent flagged in WIP and Idle statuses
+	PercentOfCycleTime float64
+	MeanTimeToUnblock  ReportDuration
+}
+
+type BlockedItem struct {
+	Key       string
+	Title     string
+	IssueType string
+	Status    string
+	Since     time.Time
+	Blocked   ReportDuration
+}
+
+type StatusCount struct {
+	Status string
+	Count  int
+}
+
+// BlockedTime summarizes the Flagged history of the issues until the end of the report window.
+// The last entry of ByType holds all issue types together.
+type BlockedTime struct {
+	ByType []TypeBlockedTime
+	// Status the issues were in when they were flagged
+	FlaggedInStatus  []StatusCount
+	CurrentlyBlocked []BlockedItem
+}
+
+type blockedTimeTotals struct {
+	issues, events, unblocked          int
+	blocked, blockedInCycle, cycleTime time.Duration
+	unblockTime                        time.Duration
+}
+
+func (t *blockedTimeTotals) add(other blockedTimeTotals) {
+	t.issues += other.issues
+	t.events += other.events
+	t.unblocked += other.unblocked
+	t.blocked += other.blocked
+	t.blockedInCycle += other.blockedInCycle
+	t.cycleTime += other.cycleTime
+	t.unblockTime += other.unblockTime
+}
+
+func (t blockedTimeTotals) toTypeBlockedTime(issueType string) TypeBlockedTime {
+	blockedTime := TypeBlockedTime{
+		IssueType: issueType,
+		Issues:    t.issues,
+		Events:    t.events,
+		Blocked:   newReportDuration(t.blocked),
+	}
+	if t.cycleTime > 0 {
+		blockedTime.PercentOfCycleTime = roundFloat(float64(t.blockedInCycle) * 100 / float64(t.cycleTime))
+	}
+	if t.unblocked > 0 {
+		blockedTime.MeanTimeToUnblock = newReportDuration(t.unblockTime / time.Duration(t.unblocked))
+	}
+	return blockedTime
+}
+
+// Flags are clipped to the report window, from windowStart when it is set to windowEnd or to the resolution
+// of the issue. Flags outside of it are ignored and flags still set count until its end.
+func getBlockedTime(issueDetailsMapByType map[string][]IssueDetails, windowStart, windowEnd time.Time) BlockedTime {
+	var blockedTime BlockedTime
+	var all blockedTimeTotals
+	flaggedInStatus := make(map[string]int)
+
+	for _, issueType := range getSortedIssueTypes(issueDetailsMapByType) {
+		var totals blockedTimeTotals
+		for _, issueDetails := range issueDetailsMapByType[issueType] {
+			// a flag left on a resolved issue no longer blocks it
+			issueEnd := windowEnd
+			if !issueDetails.ResolvedDate.IsZero() && issueDetails.ResolvedDate.Before(windowEnd) {
+				issueEnd = issueDetails.ResolvedDate
+			}
+			var flagged bool
+			for _, flag := range issueDetails.FlagDetails {
+				if !flag.FlagStart.Before(issueEnd) || (!flag.FlagEnd.IsZero() && !flag.FlagEnd.After(windowStart)) {
+					continue
+				}
+				flagged = true
+				totals.events++
+				// a copy, so the flags of the issue keep their start
+				clipped := flag
+				if windowStart.After(clipped.FlagStart) {
+					clipped.FlagStart = windowStart
+				}
+				totals.blocked += clipped.GetFlagDuration(issueEnd)
+				// the time to unblock covers the whole flag, even when it was set before the window
+				if !flag.FlagEnd.IsZero() && flag.FlagEnd.Before(issueEnd) {
+					totals.unblocked++
+					totals.unblockTime += flag.GetFlagDuration(issueEnd)
+				}
+				flaggedInStatus[issueDetails.GetStatusAt(flag.FlagStart)]++
+			}
+			if !flagged {
+				continue
+			}
+			totals.issues++
+			totals.blockedInCycle += issueDetails.GetFlaggedDurationIn(windowStart, issueEnd, wipStatusType, idleStatusType)
+			totals.cycleTime += issueDetails.GetWipAndIdleDurationIn(windowStart, issueEnd)
+
+			if issueEnd.Equal(windowEnd) && issueDetails.IsFlaggedAt(windowEnd) {
+				blockedTime.CurrentlyBlocked = append(blockedTime.CurrentlyBlocked, getBlockedItem(issueDetails, windowEnd))
+			}
+		}
+		if totals.events == 0 {
+			continue
+		}
+		blockedTime.ByType = append(blockedTime.ByType, totals.toTypeBlockedTime(issueType))
+		all.add(totals)
+	}
+	if all.events > 0 {
+		blockedTime.ByType = append(blockedTime.ByType, all.toTypeBlockedTime(allIssueTypes))
+	}
+
+	for _, status := range getSortedKeys(flaggedInStatus) {
+		blockedTime.FlaggedInStatus = append(blockedTime.FlaggedInStatus, StatusCount{Status: status, Count: flaggedInStatus[status]})
+	}
+	sort.SliceStable(blockedTime.CurrentlyBlocked, func(i, j int) bool {
+		return blockedTime.CurrentlyBlocked[i].Blocked.Value > blockedTime.CurrentlyBlocked[j].Blocked.Value
+	})
+	return blockedTime
+}
+
+func getBlockedItem(issueDetails IssueDetails, windowEnd time.Time) BlockedItem {
+	item := BlockedItem{
+		Key:       issueDetails.Key,
+		Title:     issueDetails.Title,
+		IssueType: issueDetails.IssueType,
+		Status:    issueDetails.GetStatusAt(windowEnd),
+	}
+	for _, flag := range issueDetails.FlagDetails {
+		if flag.isSetAt(windowEnd) {
+			item.Since = flag.FlagStart
+			item.Blocked = newReportDuration(flag.GetFlagDuration(windowEnd))
+		}
+	}
+	return item
+}
+
+func printBlockedTime(blockedTime BlockedTime) {
+	if len(blockedTime.ByType) == 0 {
+		return
+	}
+	title("\n> Blocked time\n")
+	fmt.Printf("%-15s %6s %6s %8s %8s %8s\n", "Type", "Issues", "Events", "Days", "Cycle%", "Unblock")
+	for _, typeBlockedTime := range blockedTime.ByType {
+		fmt.Printf("%-15s %6d %6d", typeBlockedTime.IssueType, typeBlockedTime.Issues, typeBlockedTime.Events)
+		warn(" %8.1f %7.1f%%", typeBlockedTime.Blocked.Days, typeBlockedTime.PercentOfCycleTime)
+		fmt.Printf(" %8.1f\n", typeBlockedTime.MeanTimeToUnblock.Days)
+	}
+
+	fmt.Printf("\nFlagged in status:\n")
+	for _, statusCount := range blockedTime.FlaggedInStatus {
+		fmt.Printf("- %v: %d\n", statusCount.Status, statusCount.Count)
+	}
+
+	if len(blockedTime.CurrentlyBlocked) > 0 {
+		fmt.Printf("\nCurrently blocked:\n")
+		for _, item := range blockedTime.CurrentlyBlocked {
+			fmt.Printf("%s | %s | %s | %s | Since: %s | ", color.RedString(item.Key), item.Title, item.IssueType,
+				item.Status, formatBrDate(item.Since))
+			warn("%.1f days\n", item.Blocked.Days)
+		}
+	}
+}
+
+// Returns the time spent in WIP and Idle statuses between windowStart and windowEnd
+func (i *IssueDetails) GetWipAndIdleDurationIn(windowStart, windowEnd time.Time) time.Duration {
+	var wipIdleTotal time.Duration
+	for transition := i.TransitionDetails; transition != nil && transition.PreviousTransition != nil; transition = transition.PreviousTransition {
+		if statusType := getIssueTypeByStatus(transition.StatusFrom); statusType == wipStatusType || statusType == idleStatusType {
+			wipIdleTotal += getOverlapDuration(transition.PreviousTransition.Timestamp, transition.Timestamp, windowStart, windowEnd)
+		}
+	}
+	return wipIdleTotal
+}
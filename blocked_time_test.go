@@ -0,0 +1,138 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func testTime(day, month, hour int) time.Time {
+	return time.Date(2020, time.Month(month), day, hour, 0, 0, 0, time.UTC)
+}
+
+type testTransition struct {
+	timestamp time.Time
+	status    string
+}
+
+// Issue created in Open that went through the given transitions
+func newTestIssue(key, issueType string, created time.Time, transitions ...testTransition) IssueDetails {
+	issueDetails := IssueDetails{Key: key, IssueType: issueType, CreatedDate: created}
+	previous := &TransitionDetails{Timestamp: created, StatusTo: "Open"}
+	for _, transition := range transitions {
+		previous = &TransitionDetails{
+			Timestamp:          transition.timestamp,
+			StatusFrom:         previous.StatusTo,
+			StatusTo:           transition.status,
+			PreviousTransition: previous,
+		}
+		switch getIssueTypeByStatus(transition.status) {
+		case wipStatusType:
+			if issueDetails.WipDate.IsZero() {
+				issueDetails.WipDate = transition.timestamp
+			}
+		case doneStatusType:
+			issueDetails.ResolvedDate = transition.timestamp
+		}
+	}
+	issueDetails.TransitionDetails = previous
+	return issueDetails
+}
+
+func useTestBoard() {
+	useCalendar(CalendarCfg{})
+	BoardCfg.OpenStatus = []string{"Open"}
+	BoardCfg.WipStatus = []string{"In Progress"}
+	BoardCfg.DoneStatus = []string{"Done"}
+}
+
+func TestGetFlagDuration(t *testing.T) {
+	useTestBoard()
+	windowEnd := testTime(6, 3, 0)
+	tests := []struct {
+		name string
+		flag FlagDetails
+		want time.Duration
+	}{
+		{"removed", FlagDetails{FlagStart: testTime(2, 3, 9), FlagEnd: testTime(3, 3, 9)}, 24 * time.Hour},
+		{"still set", FlagDetails{FlagStart: testTime(5, 3, 9)}, 15 * time.Hour},
+		{"removed after the window", FlagDetails{FlagStart: testTime(5, 3, 9), FlagEnd: testTime(10, 3, 9)}, 15 * time.Hour},
+		{"set after the window", FlagDetails{FlagStart: testTime(6, 3, 9)}, 0},
+		{"over a weekend", FlagDetails{FlagStart: testTime(28, 2, 9), FlagEnd: testTime(2, 3, 9)}, 24 * time.Hour},
+	}
+	for _, test := range tests {
+		flag := test.flag
+		if got := flag.GetFlagDuration(windowEnd); got != test.want {
+			t.Errorf("%v: GetFlagDuration = %v, want %v", test.name, got, test.want)
+		}
+		if flag != test.flag {
+			t.Errorf("%v: GetFlagDuration changed the flag to %+v", test.name, flag)
+		}
+	}
+}
+
+func TestGetBlockedTime(t *testing.T) {
+	useTestBoard()
+	story := newTestIssue("PRJ-1", "Story", testTime(21, 2, 9), testTransition{testTime(24, 2, 9), "In Progress"})
+	story.FlagDetails = []FlagDetails{
+		// removed before the window, ignored
+		{FlagStart: testTime(25, 2, 9), FlagEnd: testTime(26, 2, 9)},
+		// set before the window, blocked from its start but unblocked after the whole flag
+		{FlagStart: testTime(28, 2, 9), FlagEnd: testTime(3, 3, 9)},
+		// still set at the end of the window
+		{FlagStart: testTime(5, 3, 9)},
+	}
+	bug := newTestIssue("PRJ-2", "Bug", testTime(2, 3, 8), testTransition{testTime(2, 3, 9), "In Progress"},
+		testTransition{testTime(3, 3, 9), "Done"})
+	// left on the resolved issue, counted until the resolution
+	bug.FlagDetails = []FlagDetails{{FlagStart: testTime(2, 3, 12)}}
+
+	byType := getIssueDetailsMapByType([]IssueDetails{story, bug})
+	blockedTime := getBlockedTime(byType, testTime(2, 3, 0), testTime(6, 3, 0))
+
+	want := []struct {
+		issueType         string
+		issues, events    int
+		blocked           time.Duration
+		meanTimeToUnblock time.Duration
+	}{
+		{"Bug", 1, 1, 21 * time.Hour, 0},
+		{"Story", 1, 2, 48 * time.Hour, 48 * time.Hour},
+		{allIssueTypes, 2, 3, 69 * time.Hour, 48 * time.Hour},
+	}
+	if len(blockedTime.ByType) != len(want) {
+		t.Fatalf("got %d issue types, want %d: %+v", len(blockedTime.ByType), len(want), blockedTime.ByType)
+	}
+	for i, w := range want {
+		got := blockedTime.ByType[i]
+		if got.IssueType != w.issueType || got.Issues != w.issues || got.Events != w.events ||
+			got.Blocked.Value != w.blocked || got.MeanTimeToUnblock.Value != w.meanTimeToUnblock {
+			t.Errorf("ByType[%d] = %v %d issues %d events %v blocked %v to unblock, want %+v", i, got.IssueType,
+				got.Issues, got.Events, got.Blocked.Value, got.MeanTimeToUnblock.Value, w)
+		}
+	}
+
+	if len(blockedTime.FlaggedInStatus) != 1 || blockedTime.FlaggedInStatus[0] != (StatusCount{"In Progress", 3}) {
+		t.Errorf("FlaggedInStatus = %+v, want 3 flags In Progress", blockedTime.FlaggedInStatus)
+	}
+	if len(blockedTime.CurrentlyBlocked) != 1 || blockedTime.CurrentlyBlocked[0].Key != "PRJ-1" {
+		t.Errorf("CurrentlyBlocked = %+v, want PRJ-1 only", blockedTime.CurrentlyBlocked)
+	}
+	if story.FlagDetails[1].FlagStart != testTime(28, 2, 9) || !story.FlagDetails[2].FlagEnd.IsZero() {
+		t.Errorf("getBlockedTime changed the flags of the issue: %+v", story.FlagDetails)
+	}
+}
+
+func TestGetBlockedTimePercentOfCycleTime(t *testing.T) {
+	useTestBoard()
+	story := newTestIssue("PRJ-1", "Story", testTime(21, 2, 9), testTransition{testTime(24, 2, 9), "In Progress"},
+		testTransition{testTime(3, 3, 9), "Done"})
+	story.FlagDetails = []FlagDetails{{FlagStart: testTime(28, 2, 9), FlagEnd: testTime(3, 3, 9)}}
+
+	// flagged during the whole part of the cycle time inside the window
+	byType := getIssueDetailsMapByType([]IssueDetails{story})
+	blockedTime := getBlockedTime(byType, testTime(2, 3, 0), testTime(6, 3, 0))
+	if got := blockedTime.ByType[0]; got.Blocked.Value != 33*time.Hour || got.PercentOfCycleTime != 100 {
+		t.Errorf("ByType[0] = %v blocked %v%% of the cycle time, want 33h blocked 100%%", got.Blocked.Value,
+			got.PercentOfCycleTime)
+	}
+}
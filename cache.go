@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const cacheJql = "project = '%v'%v AND issuetype != Epic"
+
+// Jira compares dates in the user profile time zone, so incremental syncs look one day further back
+const cacheSyncMargin = 24 * time.Hour
+
+// Incremental syncs never see the issues deleted or moved out of the board, a full sync this often drops them
+const cacheFullSyncInterval = 7 * 24 * time.Hour
+
+// IssueCache holds every issue of the board, changelog included, keyed by issue key
+type IssueCache struct {
+	JiraUrl      string
+	Jql          string
+	LastSync     time.Time
+	LastFullSync time.Time
+	Issues       map[string]jira.Issue
+}
+
+// Syncs the cache with the issues updated since the last sync and returns the cached issues
+// whose status changed between startDate and endDate, as the default search does
+func getCachedIssues(startDate, endDate time.Time) []jira.Issue {
+	if CLParameters.Jql != "" {
+		log.Fatalf("--cache can not be used with a JQL, only with a start and end date")
+	}
+
+	cache := syncCache()
+
+	endDate = endDate.Add(time.Hour * time.Duration(24))
+	var issues []jira.Issue
+	for _, issue := range cache.Issues {
+		if statusChangedDuring(issue, startDate, endDate) || (CLParameters.Aging && isInFlight(issue)) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Key < issues[j].Key
+	})
+	if CLParameters.Debug {
+		log.Printf("%v of %v cached issues changed status in the period", len(issues), len(cache.Issues))
+	}
+	return issues
+}
+
+func syncCache() IssueCache {
+	cacheFile := getCacheFile()
+	jql := fmt.Sprintf(cacheJql, BoardCfg.Project, getBoardJqlFilter())
+	cache := loadCache(cacheFile)
+	if cache.JiraUrl != BoardCfg.JiraUrl || cache.Jql != jql {
+		cache = IssueCache{JiraUrl: BoardCfg.JiraUrl, Jql: jql}
+	}
+
+	syncTime := time.Now()
+	fullSync := cache.Issues == nil || syncTime.Sub(cache.LastFullSync) >= cacheFullSyncInterval
+	syncJql := jql
+	if !fullSync {
+		syncJql += fmt.Sprintf(" AND updated >= '%v'", cache.LastSync.Add(-cacheSyncMargin).Format("2006/01/02 15:04"))
+	}
+	updated := searchIssues(syncJql)
+	if fullSync {
+		cache.Issues = make(map[string]jira.Issue)
+		cache.LastFullSync = syncTime
+	}
+	for _, issue := range updated {
+		cache.Issues[issue.Key] = issue
+	}
+	cache.LastSync = syncTime
+
+	saveCache(cacheFile, cache)
+	if CLParameters.Debug {
+		log.Printf("Synced %v issues into cache %v, full sync: %v", len(updated), cacheFile, fullSync)
+	}
+	return cache
+}
+
+func statusChangedDuring(issue jira.Issue, startDate, endDate time.Time) bool {
+	if issue.Changelog == nil {
+		return false
+	}
+	for _, history := range issue.Changelog.Histories {
+		for _, item := range history.Items {
+			if item.Field == "status" {
+				changeTime := parseTime(history.Created)
+				if !changeTime.Before(startDate) && changeTime.Before(endDate) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// One cache file per Jira host and board, under $XDG_CACHE_HOME/jira-kanban-metrics
+func getCacheFile() string {
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		cacheDir = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+
+	name := BoardCfg.Project
+	if BoardCfg.Board != "" {
+		name = BoardCfg.Board
+	}
+	if jiraUrl, err := url.Parse(BoardCfg.JiraUrl); err == nil && jiraUrl.Host != "" {
+		name = jiraUrl.Host + "_" + name
+	}
+	name = regexp.MustCompile(`[^A-Za-z0-9._-]+`).ReplaceAllString(name, "_")
+	return filepath.Join(cacheDir, "jira-kanban-metrics", name+".json")
+}
+
+func loadCache(cacheFile string) IssueCache {
+	var cache IssueCache
+	file, err := os.Open(cacheFile)
+	if os.IsNotExist(err) {
+		return cache
+	}
+	if err != nil {
+		log.Fatalf("Failed to open cache file %v: %v", cacheFile, err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	err = decoder.Decode(&cache)
+	if err != nil {
+		log.Printf("Ignoring unreadable cache file %v: %v", cacheFile, err)
+		return IssueCache{}
+	}
+	return cache
+}
+
+// Writes to a temporary file first so an interrupted run does not leave a broken cache behind
+func saveCache(cacheFile string, cache IssueCache) {
+	err := os.MkdirAll(filepath.Dir(cacheFile), 0700)
+	if err != nil {
+		log.Fatalf("Failed to create cache directory %v: %v", filepath.Dir(cacheFile), err)
+	}
+
+	tmpFile := cacheFile + ".tmp"
+	file, err := os.Create(tmpFile)
+	if err != nil {
+		log.Fatalf("Failed to create cache file %v: %v", tmpFile, err)
+	}
+	encoder := json.NewEncoder(file)
+	err = encoder.Encode(cache)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile, cacheFile)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write cache file %v: %v", cacheFile, err)
+	}
+}
+
+func printCacheStatus() {
+	cacheFile := getCacheFile()
+	title("Cache of project %s\n", BoardCfg.Project)
+	fmt.Printf("File: ")
+	info("%s\n", cacheFile)
+
+	fileInfo, err := os.Stat(cacheFile)
+	if os.IsNotExist(err) {
+		warn("Not synced yet\n")
+		return
+	}
+	cache := loadCache(cacheFile)
+	fmt.Printf("Size: ")
+	info("%d KB\n", fileInfo.Size()/1024)
+	fmt.Printf("Jira: ")
+	info("%s\n", cache.JiraUrl)
+	fmt.Printf("JQL: ")
+	info("%s\n", cache.Jql)
+	fmt.Printf("Issues: ")
+	info("%d\n", len(cache.Issues))
+	fmt.Printf("Last sync: ")
+	info("%s\n", formatBrDateWithTime(cache.LastSync))
+	// deleted issues and issues moved out of the board stay cached until then
+	fmt.Printf("Last full sync: ")
+	info("%s\n", formatBrDateWithTime(cache.LastFullSync))
+	fmt.Printf("Next full sync: ")
+	info("%s\n", formatBrDateWithTime(cache.LastFullSync.Add(cacheFullSyncInterval)))
+}
+
+func clearCache() {
+	cacheFile := getCacheFile()
+	err := os.Remove(cacheFile)
+	if os.IsNotExist(err) {
+		fmt.Printf("Cache %s is already empty\n", cacheFile)
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to remove cache file %v: %v", cacheFile, err)
+	}
+	fmt.Printf("Removed cache %s\n", cacheFile)
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const holidayKeyFormat = "2006-01-02"
+
+// Yearly holidays of an iCalendar file without COUNT or UNTIL recur up to this many years from now
+const iCalYearlyHorizon = 10
+
+var defaultWorkingDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+type CalendarCfg struct {
+	WorkingDays  []string
+	Holidays     []string
+	HolidaysFile string
+	WorkingHours *WorkingHoursCfg
+}
+
+// WorkingHoursCfg limits durations to the working hours of each working day, e.g. 09:00 to 18:00
+type WorkingHoursCfg struct {
+	Start    string
+	End      string
+	TimeZone string
+}
+
+type WorkingCalendar struct {
+	workingDays  map[time.Weekday]bool
+	holidays     map[string]bool
+	workingHours bool
+	hoursStart   time.Duration
+	hoursEnd     time.Duration
+	location     *time.Location
+}
+
+var Calendar WorkingCalendar
+
+// Builds the working calendar from the Calendar section of the board config, defaulting to Monday to Friday
+func loadCalendar() {
+	Calendar = WorkingCalendar{
+		workingDays: make(map[time.Weekday]bool),
+		holidays:    make(map[string]bool),
+	}
+
+	if len(BoardCfg.Calendar.WorkingDays) == 0 {
+		for _, weekday := range defaultWorkingDays {
+			Calendar.workingDays[weekday] = true
+		}
+	}
+	for _, day := range BoardCfg.Calendar.WorkingDays {
+		weekday, ok := parseWeekday(day)
+		if !ok {
+			log.Fatalf("Invalid working day in calendar config: %v", day)
+		}
+		Calendar.workingDays[weekday] = true
+	}
+
+	for _, holiday := range BoardCfg.Calendar.Holidays {
+		start, end, ok := parseHoliday(holiday)
+		if !ok {
+			log.Fatalf("Invalid holiday in calendar config, expected dd/mm/yyyy or dd/mm/yyyy-dd/mm/yyyy: %v", holiday)
+		}
+		Calendar.addHolidays(start, end)
+	}
+
+	if BoardCfg.Calendar.HolidaysFile != "" {
+		loadICalHolidays(getConfigRelativePath(BoardCfg.Calendar.HolidaysFile))
+	}
+
+	if BoardCfg.Calendar.WorkingHours != nil {
+		loadWorkingHours(*BoardCfg.Calendar.WorkingHours)
+	}
+}
+
+func loadWorkingHours(workingHours WorkingHoursCfg) {
+	start, end, ok := parseWorkingHours(workingHours)
+	if !ok {
+		log.Fatalf("Invalid working hours in calendar config, expected a start and end in hh:mm format: %v-%v",
+			workingHours.Start, workingHours.End)
+	}
+
+	location, err := time.LoadLocation(workingHours.TimeZone)
+	if err != nil {
+		log.Fatalf("Invalid working hours time zone %v: %v", workingHours.TimeZone, err)
+	}
+
+	Calendar.workingHours = true
+	Calendar.hoursStart = start
+	Calendar.hoursEnd = end
+	Calendar.location = location
+}
+
+// Returns the start and end of the working hours as offsets from midnight
+func parseWorkingHours(workingHours WorkingHoursCfg) (time.Duration, time.Duration, bool) {
+	const hourFormat = "15:04"
+	start, startErr := time.Parse(hourFormat, workingHours.Start)
+	end, endErr := time.Parse(hourFormat, workingHours.End)
+	if startErr != nil || endErr != nil || !end.After(start) {
+		return 0, 0, false
+	}
+	return time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute, true
+}
+
+// Length of a working day, used to convert durations into working days
+func (c WorkingCalendar) dayLength() time.Duration {
+	if c.workingHours {
+		return c.hoursEnd - c.hoursStart
+	}
+	return 24 * time.Hour
+}
+
+func (c WorkingCalendar) durationModel() string {
+	if c.workingHours {
+		return fmt.Sprintf("working hours from %s to %s (%s)", BoardCfg.Calendar.WorkingHours.Start,
+			BoardCfg.Calendar.WorkingHours.End, c.location)
+	}
+	return "calendar time minus non working days"
+}
+
+// Sums the time between start and end that falls inside the working hours of each working day
+func (c WorkingCalendar) getWorkingHoursDuration(start, end time.Time) time.Duration {
+	var total time.Duration
+	start, end = start.In(c.location), end.In(c.location)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, c.location)
+	for day.Before(end) {
+		if c.isWorkingDay(day) {
+			from, to := c.getTimeOfDay(day, c.hoursStart), c.getTimeOfDay(day, c.hoursEnd)
+			if start.After(from) {
+				from = start
+			}
+			if end.Before(to) {
+				to = end
+			}
+			if to.After(from) {
+				total += to.Sub(from)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// Wall clock time of the day at an offset from midnight, which adding the offset gets wrong on daylight saving changes
+func (c WorkingCalendar) getTimeOfDay(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0,
+		c.location)
+}
+
+func (c WorkingCalendar) isWorkingDay(date time.Time) bool {
+	return c.workingDays[date.Weekday()] && !c.holidays[date.Format(holidayKeyFormat)]
+}
+
+// Adds every day from start to end, both inclusive
+func (c WorkingCalendar) addHolidays(start, end time.Time) {
+	for dateIndex := start; !dateIndex.After(end); dateIndex = dateIndex.AddDate(0, 0, 1) {
+		c.holidays[dateIndex.Format(holidayKeyFormat)] = true
+	}
+}
+
+func parseWeekday(day string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.ToUpper(weekday.String()) == strings.ToUpper(strings.TrimSpace(day)) {
+			return weekday, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// holiday: DD/MM/YYYY or DD/MM/YYYY-DD/MM/YYYY
+func parseHoliday(holiday string) (time.Time, time.Time, bool) {
+	dates := strings.Split(holiday, "-")
+	start := parseDate(strings.TrimSpace(dates[0]))
+	end := start
+	if len(dates) == 2 {
+		end = parseDate(strings.TrimSpace(dates[1]))
+	}
+	if len(dates) > 2 || start.IsZero() || end.IsZero() || end.Before(start) {
+		return start, end, false
+	}
+	return start, end, true
+}
+
+// Reads the all day events of an iCalendar file as holidays, DTEND being exclusive as in the RFC 5545
+func loadICalHolidays(fileName string) {
+	var start, end time.Time
+	var rule string
+	for _, line := range readICalLines(fileName) {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "BEGIN:VEVENT":
+			start, end, rule = time.Time{}, time.Time{}, ""
+		case strings.HasPrefix(line, "DTSTART"):
+			start = parseICalDate(line)
+		case strings.HasPrefix(line, "DTEND"):
+			end = parseICalDate(line)
+		case strings.HasPrefix(line, "RRULE:"):
+			rule = strings.TrimPrefix(line, "RRULE:")
+		case line == "END:VEVENT":
+			// timed events are not holidays
+			if start.IsZero() {
+				continue
+			}
+			days := 1
+			if end.After(start) {
+				days = int(end.Sub(start).Hours() / 24)
+			}
+			for _, occurrence := range getICalOccurrences(start, rule) {
+				Calendar.addHolidays(occurrence, occurrence.AddDate(0, 0, days-1))
+			}
+		}
+	}
+}
+
+// Content lines of the file, with the lines folded by a leading space or tab joined back (RFC 5545 3.1)
+func readICalLines(fileName string) []string {
+	file, err := os.Open(fileName)
+	if err != nil {
+		log.Fatalf("Failed to open holidays file %v: %v", fileName, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("Failed to read holidays file %v: %v", fileName, err)
+	}
+	return lines
+}
+
+// line: DTSTART;VALUE=DATE:20200224, date-time values like DTSTART:20200224T000000Z give a zero time
+func parseICalDate(line string) time.Time {
+	return parseICalDateValue(line[strings.LastIndex(line, ":")+1:])
+}
+
+func parseICalDateValue(value string) time.Time {
+	const iCalDateFormat = "20060102"
+	date, err := time.Parse(iCalDateFormat, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+// Dates of an event given its recurrence rule. Only yearly rules are expanded, up to their COUNT or UNTIL or
+// iCalYearlyHorizon years from now, other rules keep the first occurrence only.
+func getICalOccurrences(start time.Time, rule string) []time.Time {
+	if rule == "" {
+		return []time.Time{start}
+	}
+
+	until := time.Date(time.Now().Year()+iCalYearlyHorizon, time.December, 31, 0, 0, 0, 0, time.UTC)
+	interval, count := 1, 0
+	supported := true
+	for _, part := range strings.Split(rule, ";") {
+		var err error
+		name, value := part, ""
+		if i := strings.Index(part, "="); i >= 0 {
+			name, value = strings.ToUpper(part[:i]), part[i+1:]
+		}
+		switch name {
+		case "FREQ":
+			supported = supported && value == "YEARLY"
+		case "INTERVAL":
+			interval, err = strconv.Atoi(value)
+			supported = supported && err == nil && interval > 0
+		case "COUNT":
+			count, err = strconv.Atoi(value)
+			supported = supported && err == nil && count > 0
+		case "UNTIL":
+			if len(value) >= 8 {
+				until = parseICalDateValue(value[:8])
+			}
+			supported = supported && !until.IsZero()
+		case "BYMONTH":
+			supported = supported && value == strconv.Itoa(int(start.Month()))
+		case "BYMONTHDAY":
+			supported = supported && value == strconv.Itoa(start.Day())
+		default:
+			supported = false
+		}
+	}
+	if !supported {
+		log.Printf("Warning: unsupported recurrence rule %v, only the holiday on %v is used", rule, formatBrDate(start))
+		return []time.Time{start}
+	}
+
+	var occurrences []time.Time
+	for years := 0; count == 0 || len(occurrences) < count; years += interval {
+		occurrence := start.AddDate(years, 0, 0)
+		if occurrence.After(until) {
+			break
+		}
+		// February 29th only recurs on leap years
+		if occurrence.Day() == start.Day() {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences
+}
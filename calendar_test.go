@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Loads the working calendar from the given config, as loadBoardCfg does
+func useCalendar(calendar CalendarCfg) {
+	BoardCfg = BoardConfig{Calendar: calendar}
+	loadCalendar()
+}
+
+func TestParseHoliday(t *testing.T) {
+	tests := []struct {
+		holiday string
+		start   string
+		end     string
+		ok      bool
+	}{
+		{"25/12/2019", "25/12/2019", "25/12/2019", true},
+		{"24/02/2020-26/02/2020", "24/02/2020", "26/02/2020", true},
+		{" 24/02/2020 - 26/02/2020 ", "24/02/2020", "26/02/2020", true},
+		{"26/02/2020-24/02/2020", "", "", false},
+		{"2020-02-24", "", "", false},
+		{"24/02/2020-25/02/2020-26/02/2020", "", "", false},
+	}
+	for _, test := range tests {
+		start, end, ok := parseHoliday(test.holiday)
+		if ok != test.ok {
+			t.Errorf("parseHoliday(%q) ok = %v, want %v", test.holiday, ok, test.ok)
+			continue
+		}
+		if ok && (!start.Equal(parseDate(test.start)) || !end.Equal(parseDate(test.end))) {
+			t.Errorf("parseHoliday(%q) = %v, %v, want %v, %v", test.holiday, start, end, test.start, test.end)
+		}
+	}
+}
+
+func TestGetWorkingDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar CalendarCfg
+		start    string
+		end      string
+		want     []string
+	}{
+		{"weekend", CalendarCfg{}, "14/02/2020", "17/02/2020", []string{"14/02/2020", "17/02/2020"}},
+		{"holiday range", CalendarCfg{Holidays: []string{"24/02/2020-25/02/2020"}}, "24/02/2020", "28/02/2020",
+			[]string{"26/02/2020", "27/02/2020", "28/02/2020"}},
+		{"working days", CalendarCfg{WorkingDays: []string{"monday", "Saturday"}}, "17/02/2020", "24/02/2020",
+			[]string{"17/02/2020", "22/02/2020", "24/02/2020"}},
+		{"ics file", CalendarCfg{HolidaysFile: "testdata/holidays.ics"}, "24/02/2020", "28/02/2020",
+			[]string{"26/02/2020", "27/02/2020", "28/02/2020"}},
+		{"ics event without end", CalendarCfg{HolidaysFile: "testdata/holidays.ics"}, "09/04/2020", "13/04/2020",
+			[]string{"09/04/2020", "13/04/2020"}},
+		{"ics timed event", CalendarCfg{HolidaysFile: "testdata/holidays.ics"}, "20/04/2020", "22/04/2020",
+			[]string{"20/04/2020", "21/04/2020", "22/04/2020"}},
+		{"ics folded yearly event", CalendarCfg{HolidaysFile: "testdata/holidays.ics"}, "31/12/2020", "04/01/2021",
+			[]string{"31/12/2020", "04/01/2021"}},
+		{"ics yearly event with count", CalendarCfg{HolidaysFile: "testdata/holidays.ics"}, "07/09/2020", "08/09/2020",
+			[]string{"08/09/2020"}},
+		{"ics yearly event after its count", CalendarCfg{HolidaysFile: "testdata/holidays.ics"}, "07/09/2021",
+			"07/09/2021", []string{"07/09/2021"}},
+	}
+	for _, test := range tests {
+		useCalendar(test.calendar)
+		var got []string
+		for _, day := range getWorkingDays(parseDate(test.start), parseDate(test.end)) {
+			got = append(got, formatBrDate(day))
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%v: getWorkingDays = %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%v: getWorkingDays = %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGetNextWorkingDays(t *testing.T) {
+	useCalendar(CalendarCfg{WorkingDays: []string{"Monday"}, Holidays: []string{"01/01/2020-31/03/2020"}})
+	days := getNextWorkingDays(parseDate("01/01/2020"), 10)
+	if len(days) != 10 {
+		t.Fatalf("got %d working days, want 10", len(days))
+	}
+	if first := formatBrDate(days[0]); first != "06/04/2020" {
+		t.Errorf("first working day = %v, want 06/04/2020", first)
+	}
+	for _, day := range days {
+		if day.Weekday() != time.Monday {
+			t.Errorf("%v is not a Monday", formatBrDate(day))
+		}
+	}
+}
+
+func TestGetWorkingHoursDuration(t *testing.T) {
+	useCalendar(CalendarCfg{
+		WorkingDays:  []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+		Holidays:     []string{"13/03/2019"},
+		WorkingHours: &WorkingHoursCfg{Start: "09:00", End: "18:00", TimeZone: "America/New_York"},
+	})
+	location := Calendar.location
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2019, 3, day, hour, minute, 0, 0, location)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       time.Duration
+	}{
+		{"same day", at(11, 10, 0), at(11, 12, 30), 2*time.Hour + 30*time.Minute},
+		{"before and after hours", at(11, 7, 0), at(11, 20, 0), 9 * time.Hour},
+		{"overnight", at(11, 17, 0), at(12, 10, 0), 2 * time.Hour},
+		{"holiday", at(12, 17, 0), at(14, 10, 0), 2 * time.Hour},
+		{"non working day", at(8, 17, 0), at(10, 10, 0), 2 * time.Hour},
+		{"daylight saving start", at(10, 9, 0), at(10, 12, 0), 3 * time.Hour},
+		{"other time zone", time.Date(2019, 3, 11, 14, 0, 0, 0, time.UTC), at(11, 12, 0), 2 * time.Hour},
+		{"end before start", at(11, 12, 0), at(11, 10, 0), 0},
+	}
+	for _, test := range tests {
+		if got := Calendar.getWorkingHoursDuration(test.start, test.end); got != test.want {
+			t.Errorf("%v: getWorkingHoursDuration = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var statusTypes = []string{openStatusType, wipStatusType, idleStatusType, doneStatusType, notMappedStatusType}
+
+type CumulativeFlowDay struct {
+	Date         time.Time
+	ByStatus     map[string]int
+	ByStatusType map[string]int
+}
+
+type CumulativeFlow struct {
+	Statuses    []string
+	StatusTypes []string
+	Days        []CumulativeFlowDay
+}
+
+// Counts how many issues were in each status at the end of every working day of the period
+func getCumulativeFlow(issueDetails []IssueDetails, startDate, endDate time.Time) CumulativeFlow {
+	cumulativeFlow := CumulativeFlow{
+		Statuses:    getCumulativeFlowStatuses(issueDetails),
+		StatusTypes: statusTypes,
+	}
+	for _, day := range getWorkingDays(startDate, endDate) {
+		endOfDay := day.Add(time.Hour * time.Duration(24))
+		flowDay := CumulativeFlowDay{
+			Date:         day,
+			ByStatus:     make(map[string]int),
+			ByStatusType: make(map[string]int),
+		}
+		for _, issueDetails := range issueDetails {
+			if status := issueDetails.GetStatusAt(endOfDay); status != "" {
+				flowDay.ByStatus[getConfiguredStatusName(status)]++
+				flowDay.ByStatusType[getIssueTypeByStatus(status)]++
+			}
+		}
+		cumulativeFlow.Days = append(cumulativeFlow.Days, flowDay)
+	}
+	return cumulativeFlow
+}
+
+// Configured statuses in board order followed by the ones found in the issues but not mapped
+func getCumulativeFlowStatuses(issueDetails []IssueDetails) []string {
+	statuses := getConfiguredStatuses()
+	var notMapped []string
+	for status := range getNotMapped(issueDetails) {
+		notMapped = append(notMapped, status)
+	}
+	sort.Strings(notMapped)
+	return append(statuses, notMapped...)
+}
+
+func writeCumulativeFlow(cumulativeFlow CumulativeFlow) {
+	switch CLParameters.Format {
+	case "text":
+		printCumulativeFlow(cumulativeFlow)
+	case "json":
+		withOutput(func(output io.Writer) {
+			writeJSON(output, cumulativeFlow, "cumulative flow")
+		})
+	case "csv":
+		withOutput(func(output io.Writer) {
+			printCumulativeFlowCSV(output, cumulativeFlow)
+		})
+	default:
+		log.Fatalf("Unknown output format: %v", CLParameters.Format)
+	}
+}
+
+func printCumulativeFlow(cumulativeFlow CumulativeFlow) {
+	title("Cumulative flow of project %s // ", BoardCfg.Project)
+	title("From %s to %s\n", CLParameters.StartDate, CLParameters.EndDate)
+
+	title("\n> By Status\n")
+	printCumulativeFlowTable(cumulativeFlow.Days, cumulativeFlow.Statuses, func(day CumulativeFlowDay) map[string]int {
+		return day.ByStatus
+	})
+	title("\n> By Status Type\n")
+	printCumulativeFlowTable(cumulativeFlow.Days, cumulativeFlow.StatusTypes, func(day CumulativeFlowDay) map[string]int {
+		return day.ByStatusType
+	})
+}
+
+func printCumulativeFlowTable(days []CumulativeFlowDay, columns []string, counts func(CumulativeFlowDay) map[string]int) {
+	fmt.Printf("%-10s", "Date")
+	for _, column := range columns {
+		fmt.Printf("  %*s", getColumnWidth(column), column)
+	}
+	fmt.Println()
+	for _, day := range days {
+		info("%-10s", formatBrDate(day.Date))
+		for _, column := range columns {
+			fmt.Printf("  %*d", getColumnWidth(column), counts(day)[column])
+		}
+		fmt.Println()
+	}
+}
+
+func getColumnWidth(column string) int {
+	const minWidth = 5
+	if len(column) < minWidth {
+		return minWidth
+	}
+	return len(column)
+}
+
+func printCumulativeFlowCSV(output io.Writer, cumulativeFlow CumulativeFlow) {
+	header := append(append([]string{"Date"}, cumulativeFlow.Statuses...), cumulativeFlow.StatusTypes...)
+	records := [][]string{header}
+	for _, day := range cumulativeFlow.Days {
+		row := []string{formatBrDate(day.Date)}
+		for _, status := range cumulativeFlow.Statuses {
+			row = append(row, strconv.Itoa(day.ByStatus[status]))
+		}
+		for _, statusType := range cumulativeFlow.StatusTypes {
+			row = append(row, strconv.Itoa(day.ByStatusType[statusType]))
+		}
+		records = append(records, row)
+	}
+	writeCSV(output, records, "cumulative flow")
+}
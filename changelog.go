@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"github.com/zchee/color"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const changelogPageSize = 100
+
+type changelogPage struct {
+	StartAt    int                     `json:"startAt"`
+	MaxResults int                     `json:"maxResults"`
+	Total      int                     `json:"total"`
+	IsLast     bool                    `json:"isLast"`
+	Values     []jira.ChangelogHistory `json:"values"`
+}
+
+// Search truncates the expanded changelog of issues with long histories, those are fetched again in full
+func completeChangelogs(issues []jira.Issue, changelogTotals []int) {
+	var truncated []int
+	for i := range issues {
+		if i < len(changelogTotals) && changelogTotals[i] > countHistories(issues[i]) {
+			truncated = append(truncated, i)
+		}
+	}
+	if len(truncated) == 0 {
+		return
+	}
+
+	progress := newProgressBar("Fetching changelogs", len(truncated))
+	err := runConcurrently(len(truncated), func(i int) error {
+		issue := &issues[truncated[i]]
+		histories, err := getFullChangelog(issue.Key)
+		if err != nil {
+			return err
+		}
+		issue.Changelog = &jira.Changelog{Histories: histories}
+		progress.add(1)
+		return nil
+	})
+	progress.finish()
+	if err != nil {
+		log.Fatalf("Failed to fetch issue changelog on jira: %v", err)
+	}
+
+	if CLParameters.Debug {
+		var keys []string
+		for _, i := range truncated {
+			keys = append(keys, issues[i].Key)
+		}
+		debugWarn(color.Error, "Changelog truncated by the search, fetched separately: %v\n", strings.Join(keys, ", "))
+	}
+}
+
+func countHistories(issue jira.Issue) int {
+	if issue.Changelog == nil {
+		return 0
+	}
+	return len(issue.Changelog.Histories)
+}
+
+// Pages through the issue changelog endpoint, falling back to the issue expanded changelog
+// on Jira versions without that endpoint
+func getFullChangelog(key string) ([]jira.ChangelogHistory, error) {
+	var histories []jira.ChangelogHistory
+	for startAt := 0; ; {
+		var page changelogPage
+		u := fmt.Sprintf("rest/api/2/issue/%s/changelog?startAt=%d&maxResults=%d", key, startAt, changelogPageSize)
+		req, err := JiraClient.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = callJira(fmt.Sprintf("changelog of %v starting at %v", key, startAt), func() (*jira.Response, error) {
+			resp, err := JiraClient.Do(req, &page)
+			if err != nil {
+				return resp, jira.NewJiraError(resp, err)
+			}
+			return resp, nil
+		})
+		if callErr, ok := err.(*jiraCallError); ok && callErr.statusCode == http.StatusNotFound && startAt == 0 {
+			return getExpandedChangelog(key)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		histories = append(histories, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			return histories, nil
+		}
+	}
+}
+
+func getExpandedChangelog(key string) ([]jira.ChangelogHistory, error) {
+	var issue *jira.Issue
+	err := callJira(fmt.Sprintf("issue %v with changelog", key), func() (resp *jira.Response, err error) {
+		issue, resp, err = JiraClient.Issue.Get(key, &jira.GetQueryOptions{Expand: "changelog", Fields: "none"})
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if issue.Changelog == nil {
+		return nil, nil
+	}
+	return issue.Changelog.Histories, nil
+}
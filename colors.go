@@ -9,10 +9,14 @@ var titleLn = color.New(color.Bold, color.FgBlue).PrintlnFunc()
 var info = color.New(color.Bold, color.FgYellow).PrintfFunc()
 var infoLn = color.New(color.Bold, color.FgYellow).PrintlnFunc()
 var warn = color.New(color.Bold, color.FgRed).PrintfFunc()
-var debug = color.New(color.Bold, color.FgGreen).PrintlnFunc()
+
+// Debug output goes to stderr, so it does not mix with a json, csv or html report written to stdout
+var debug = color.New(color.Bold, color.FgGreen).FprintlnFunc()
+var debugInfo = color.New(color.Bold, color.FgYellow).FprintfFunc()
+var debugWarn = color.New(color.Bold, color.FgRed).FprintfFunc()
 
 func Debug(msg string) {
 	if CLParameters.Debug {
-		debug(msg)
+		debug(color.Error, msg)
 	}
 }
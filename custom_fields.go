@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"github.com/zchee/color"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported values of CustomFieldCfg.Type
+const (
+	stringFieldType      = "string"
+	numberFieldType      = "number"
+	optionFieldType      = "option"
+	multiOptionFieldType = "multi-option"
+	sprintFieldType      = "sprint"
+	userFieldType        = "user"
+	dateFieldType        = "date"
+	flagFieldType        = "flag"
+)
+
+var customFieldTypes = []string{stringFieldType, numberFieldType, optionFieldType, multiOptionFieldType,
+	sprintFieldType, userFieldType, dateFieldType, flagFieldType}
+
+// CustomFieldCfg declares a custom field to read from the issues, by ID (customfield_10021) or by name
+type CustomFieldCfg struct {
+	Id   string `json:",omitempty"`
+	Name string `json:",omitempty"`
+	Type string
+}
+
+// Fields used when the board config declares no CustomFields
+var defaultCustomFields = []CustomFieldCfg{
+	{Id: "customfield_10021", Name: "Sprint", Type: sprintFieldType},
+	{Id: "customfield_10035", Name: "Flag", Type: flagFieldType},
+}
+
+func (c CustomFieldCfg) label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Id
+}
+
+type CustomField interface {
+	Id() string
+	Name() string
+	// Plain value, used for grouping
+	Value() string
+	String() string
+	Unmarshall(interface{}) CustomField
+}
+
+// Custom fields read from the issues, built from the board config by loadCustomFields
+var supportedCustomFields []CustomField
+
+// Custom fields of the board config, or the default Sprint and Flag fields when the section is missing
+func getCustomFieldsCfg() []CustomFieldCfg {
+	if BoardCfg.CustomFields == nil {
+		return defaultCustomFields
+	}
+	return BoardCfg.CustomFields
+}
+
+func loadCustomFields() {
+	supportedCustomFields = nil
+	for _, cfg := range getCustomFieldsCfg() {
+		// fields configured by name are added once resolveCustomFields finds their ID
+		if cfg.Id != "" {
+			supportedCustomFields = append(supportedCustomFields, newCustomField(cfg))
+		}
+	}
+}
+
+// Fields configured by name need Jira to find their ID, so they are left out when reading a snapshot
+func warnUnresolvedCustomFields() {
+	for _, cfg := range BoardCfg.CustomFields {
+		if cfg.Id == "" {
+			log.Printf("Warning: custom field %v is configured by name, set its Id to read it from a snapshot", cfg.Name)
+		}
+	}
+}
+
+func newCustomField(cfg CustomFieldCfg) CustomField {
+	switch strings.ToLower(cfg.Type) {
+	case numberFieldType:
+		return NumberCustomField{Cfg: cfg}
+	case optionFieldType, multiOptionFieldType:
+		return OptionCustomField{Cfg: cfg}
+	case sprintFieldType:
+		return SprintCustomField{Cfg: cfg}
+	case userFieldType:
+		return UserCustomField{Cfg: cfg}
+	case dateFieldType:
+		return DateCustomField{Cfg: cfg}
+	case flagFieldType:
+		return FlagCustomField{Cfg: cfg}
+	default:
+		return StringCustomField{Cfg: cfg}
+	}
+}
+
+// Looks up the ID of the custom fields configured by name with the Jira field list
+func resolveCustomFields() {
+	var unresolved bool
+	for _, cfg := range BoardCfg.CustomFields {
+		unresolved = unresolved || cfg.Id == ""
+	}
+	if !unresolved {
+		return
+	}
+
+	var fields []jira.Field
+	err := callJira("fields", func() (*jira.Response, error) {
+		var resp *jira.Response
+		var err error
+		fields, resp, err = JiraClient.Field.GetList()
+		return resp, err
+	})
+	if err != nil {
+		log.Fatalf("Failed to get fields from jira: %v", err)
+	}
+
+	for i, cfg := range BoardCfg.CustomFields {
+		if cfg.Id != "" {
+			continue
+		}
+		for _, field := range fields {
+			if strings.ToUpper(field.Name) == strings.ToUpper(cfg.Name) {
+				BoardCfg.CustomFields[i].Id = field.ID
+				break
+			}
+		}
+		if BoardCfg.CustomFields[i].Id == "" {
+			log.Fatalf("Custom field %v not found in jira", cfg.Name)
+		}
+		if CLParameters.Debug {
+			log.Printf("Custom field %v is %v", cfg.Name, BoardCfg.CustomFields[i].Id)
+		}
+	}
+	loadCustomFields()
+}
+
+// Reads the configured custom fields of the issue, in the order of the board config
+func getCustomFields(issue jira.Issue) []CustomField {
+	var customFields []CustomField
+	for _, supportedCustomField := range supportedCustomFields {
+		value := issue.Fields.Unknowns[supportedCustomField.Id()]
+		if value == nil {
+			continue
+		}
+		switch customField := value.(type) {
+		case []interface{}:
+			for _, field := range customField {
+				customFields = append(customFields, supportedCustomField.Unmarshall(field))
+			}
+		case interface{}:
+			customFields = append(customFields, supportedCustomField.Unmarshall(customField))
+		}
+	}
+	return customFields
+}
+
+// Returns the values of the custom field with the given name or ID, one per value of multi valued fields
+func getCustomFieldValues(customFields []CustomField, field string) []string {
+	var values []string
+	for _, customField := range customFields {
+		if strings.ToUpper(customField.Name()) == strings.ToUpper(field) || customField.Id() == field {
+			if value := customField.Value(); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+type StringCustomField struct {
+	Cfg  CustomFieldCfg
+	Text string
+}
+
+func (cf StringCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf StringCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf StringCustomField) Value() string {
+	return cf.Text
+}
+
+func (cf StringCustomField) String() string {
+	return color.CyanString("%v: %v", cf.Name(), cf.Text)
+}
+
+func (cf StringCustomField) Unmarshall(data interface{}) CustomField {
+	cf.Text = fmt.Sprint(data)
+	return cf
+}
+
+type NumberCustomField struct {
+	Cfg    CustomFieldCfg
+	Number float64
+}
+
+func (cf NumberCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf NumberCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf NumberCustomField) Value() string {
+	return strconv.FormatFloat(cf.Number, 'f', -1, 64)
+}
+
+func (cf NumberCustomField) String() string {
+	return color.CyanString("%v: %v", cf.Name(), cf.Value())
+}
+
+func (cf NumberCustomField) Unmarshall(data interface{}) CustomField {
+	switch number := data.(type) {
+	case float64:
+		cf.Number = number
+	case string:
+		cf.Number, _ = strconv.ParseFloat(number, 64)
+	}
+	return cf
+}
+
+// Single and multiple choice fields, a multiple choice field gives one OptionCustomField per selected option
+type OptionCustomField struct {
+	Cfg    CustomFieldCfg
+	Option string
+}
+
+func (cf OptionCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf OptionCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf OptionCustomField) Value() string {
+	return cf.Option
+}
+
+func (cf OptionCustomField) String() string {
+	return color.CyanString("%v: %v", cf.Name(), cf.Option)
+}
+
+func (cf OptionCustomField) Unmarshall(data interface{}) CustomField {
+	if m, ok := data.(map[string]interface{}); ok {
+		if value, ok := m["value"].(string); ok {
+			cf.Option = value
+		}
+	} else if value, ok := data.(string); ok {
+		cf.Option = value
+	}
+	return cf
+}
+
+type SprintCustomField struct {
+	Cfg          CustomFieldCfg
+	SprintName   string
+	State        string
+	StartDate    time.Time
+	EndDate      time.Time
+	CompleteDate time.Time
+}
+
+func (cf SprintCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf SprintCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf SprintCustomField) Value() string {
+	return cf.SprintName
+}
+
+func (cf SprintCustomField) String() string {
+	return color.CyanString(cf.SprintName)
+}
+
+// Jira Server returns sprints as strings such as com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,state=CLOSED,name=Sprint 1,...]
+var sprintStringField = regexp.MustCompile(`(\w+)=([^,\]]*)`)
+
+func (cf SprintCustomField) Unmarshall(data interface{}) CustomField {
+	m, ok := data.(map[string]interface{})
+	if sprint, isString := data.(string); isString {
+		m = make(map[string]interface{})
+		for _, match := range sprintStringField.FindAllStringSubmatch(sprint, -1) {
+			if match[2] != "<null>" {
+				m[match[1]] = match[2]
+			}
+		}
+	} else if !ok {
+		return cf
+	}
+	if name, ok := m["name"].(string); ok {
+		cf.SprintName = name
+	}
+	if state, ok := m["state"].(string); ok {
+		cf.State = state
+	}
+	if startDateStr, ok := m["startDate"].(string); ok {
+		if startDate, err := time.Parse(time.RFC3339, startDateStr); err == nil {
+			cf.StartDate = startDate
+		}
+	}
+	if endDateStr, ok := m["endDate"].(string); ok {
+		if endDate, err := time.Parse(time.RFC3339, endDateStr); err == nil {
+			cf.EndDate = endDate
+		}
+	}
+	if completedDateStr, ok := m["completeDate"].(string); ok {
+		if completedDate, err := time.Parse(time.RFC3339, completedDateStr); err == nil {
+			cf.CompleteDate = completedDate
+		}
+	}
+	return cf
+}
+
+type UserCustomField struct {
+	Cfg         CustomFieldCfg
+	DisplayName string
+}
+
+func (cf UserCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf UserCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf UserCustomField) Value() string {
+	return cf.DisplayName
+}
+
+func (cf UserCustomField) String() string {
+	return color.CyanString("%v: %v", cf.Name(), cf.DisplayName)
+}
+
+func (cf UserCustomField) Unmarshall(data interface{}) CustomField {
+	if m, ok := data.(map[string]interface{}); ok {
+		for _, key := range []string{"displayName", "name", "accountId"} {
+			if value, ok := m[key].(string); ok && value != "" {
+				cf.DisplayName = value
+				break
+			}
+		}
+	}
+	return cf
+}
+
+type DateCustomField struct {
+	Cfg  CustomFieldCfg
+	Date time.Time
+}
+
+func (cf DateCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf DateCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf DateCustomField) Value() string {
+	if cf.Date.IsZero() {
+		return ""
+	}
+	return formatBrDate(cf.Date)
+}
+
+func (cf DateCustomField) String() string {
+	return color.CyanString("%v: %v", cf.Name(), cf.Value())
+}
+
+// Date fields hold 2006-01-02, date time fields 2006-01-02T15:04:05.000-0700
+func (cf DateCustomField) Unmarshall(data interface{}) CustomField {
+	if value, ok := data.(string); ok {
+		if date, err := time.Parse("2006-01-02", value); err == nil {
+			cf.Date = date
+		} else {
+			cf.Date = parseTime(value)
+		}
+	}
+	return cf
+}
+
+// Checkbox fields such as Flagged, shown by the field name when set
+type FlagCustomField struct {
+	Cfg  CustomFieldCfg
+	Flag string
+}
+
+func (cf FlagCustomField) Id() string {
+	return cf.Cfg.Id
+}
+
+func (cf FlagCustomField) Name() string {
+	return cf.Cfg.label()
+}
+
+func (cf FlagCustomField) Value() string {
+	return cf.Flag
+}
+
+func (cf FlagCustomField) String() string {
+	return color.RedString(cf.Name())
+}
+
+func (cf FlagCustomField) Unmarshall(data interface{}) CustomField {
+	if m, ok := data.(map[string]interface{}); ok {
+		if value, ok := m["value"].(string); ok {
+			cf.Flag = value
+		}
+	}
+	return cf
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Width of the buckets of the flow efficiency histogram, in percentage points
+const flowEfficiencyBucketWidth = 10
+
+// Width of the longest bar of the flow efficiency histogram, in characters
+const flowEfficiencyHistogramWidth = 25
+
+type FlowEfficiencyBucket struct {
+	From  int
+	To    int
+	Count int
+}
+
+type TypeFlowEfficiency struct {
+	IssueType  string
+	Issues     int
+	Active     ReportDuration
+	Total      ReportDuration
+	Efficiency float64
+}
+
+// FlowEfficiency relates the time spent in WIP statuses (active) to the cycle time, WIP and Idle statuses together.
+// Aggregates only consider resolved issues, the last entry of ByType holding all issue types together.
+type FlowEfficiency struct {
+	FlaggedAsWaiting bool
+	ByType           []TypeFlowEfficiency
+	Distribution     []FlowEfficiencyBucket
+}
+
+// Returns the active time of the issue, WIP time minus the flagged time inside WIP statuses
+// when flagged time counts as waiting
+func (i *IssueDetails) GetActiveDuration(flaggedAsWaiting bool, windowEnd time.Time) time.Duration {
+	active := i.GetWipTotalDuration()
+	if flaggedAsWaiting {
+		active -= i.GetFlaggedDurationIn(time.Time{}, windowEnd, wipStatusType)
+	}
+	return active
+}
+
+// Returns the time the issue was flagged while in a status of the given status types, between windowStart
+// and windowEnd
+func (i *IssueDetails) GetFlaggedDurationIn(windowStart, windowEnd time.Time, statusTypes ...string) time.Duration {
+	var flaggedTotal time.Duration
+	if len(i.FlagDetails) == 0 {
+		return flaggedTotal
+	}
+	for transition := i.TransitionDetails; transition != nil && transition.PreviousTransition != nil; transition = transition.PreviousTransition {
+		if !containsIgnoringCase(statusTypes, getIssueTypeByStatus(transition.StatusFrom)) {
+			continue
+		}
+		statusStart, statusEnd := transition.PreviousTransition.Timestamp, transition.Timestamp
+		if windowStart.After(statusStart) {
+			statusStart = windowStart
+		}
+		if windowEnd.Before(statusEnd) {
+			statusEnd = windowEnd
+		}
+		if !statusEnd.After(statusStart) {
+			continue
+		}
+		statusDuration := getTransitionDuration(statusStart, statusEnd)
+		var flagged time.Duration
+		for _, flag := range i.FlagDetails {
+			flagged += getOverlapDuration(statusStart, statusEnd, flag.FlagStart, flag.getEnd(windowEnd))
+		}
+		if flagged > statusDuration {
+			flagged = statusDuration
+		}
+		flaggedTotal += flagged
+	}
+	return flaggedTotal
+}
+
+// Working time shared by both periods
+func getOverlapDuration(start1, end1, start2, end2 time.Time) time.Duration {
+	start, end := start1, end1
+	if start2.After(start) {
+		start = start2
+	}
+	if end2.Before(end) {
+		end = end2
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return getTransitionDuration(start, end)
+}
+
+// Active time over cycle time in percent, false when the issue has no cycle time
+func getFlowEfficiency(active, total time.Duration) (float64, bool) {
+	if total <= 0 {
+		return 0, false
+	}
+	return roundFloat(float64(active) * 100 / float64(total)), true
+}
+
+func getFlowEfficiencies(issueDetailsMapByType map[string][]IssueDetails, windowEnd time.Time) FlowEfficiency {
+	flowEfficiency := FlowEfficiency{FlaggedAsWaiting: CLParameters.FlaggedAsWaiting}
+	for bucket := 0; bucket < 100; bucket += flowEfficiencyBucketWidth {
+		flowEfficiency.Distribution = append(flowEfficiency.Distribution, FlowEfficiencyBucket{
+			From: bucket,
+			To:   bucket + flowEfficiencyBucketWidth,
+		})
+	}
+
+	all := TypeFlowEfficiency{IssueType: allIssueTypes}
+	var allActive, allTotal time.Duration
+	for _, issueType := range getSortedIssueTypes(issueDetailsMapByType) {
+		typeEfficiency := TypeFlowEfficiency{IssueType: issueType}
+		var typeActive, typeTotal time.Duration
+		for _, issueDetails := range issueDetailsMapByType[issueType] {
+			if issueDetails.ResolvedDate.IsZero() {
+				continue
+			}
+			active := issueDetails.GetActiveDuration(CLParameters.FlaggedAsWaiting, windowEnd)
+			total := issueDetails.GetWipAndIdleTotalDuration()
+			efficiency, ok := getFlowEfficiency(active, total)
+			if !ok {
+				continue
+			}
+			typeEfficiency.Issues++
+			typeActive += active
+			typeTotal += total
+			bucket := int(math.Min(efficiency, 99.99)) / flowEfficiencyBucketWidth
+			flowEfficiency.Distribution[bucket].Count++
+		}
+		if typeEfficiency.Issues == 0 {
+			continue
+		}
+		typeEfficiency.Active, typeEfficiency.Total = newReportDuration(typeActive), newReportDuration(typeTotal)
+		typeEfficiency.Efficiency, _ = getFlowEfficiency(typeActive, typeTotal)
+		flowEfficiency.ByType = append(flowEfficiency.ByType, typeEfficiency)
+
+		all.Issues += typeEfficiency.Issues
+		allActive += typeActive
+		allTotal += typeTotal
+	}
+	if all.Issues > 0 {
+		all.Active, all.Total = newReportDuration(allActive), newReportDuration(allTotal)
+		all.Efficiency, _ = getFlowEfficiency(allActive, allTotal)
+		flowEfficiency.ByType = append(flowEfficiency.ByType, all)
+	}
+	return flowEfficiency
+}
+
+func printFlowEfficiency(flowEfficiency FlowEfficiency) {
+	if len(flowEfficiency.ByType) == 0 {
+		return
+	}
+	title("\n> Flow efficiency\n")
+	if flowEfficiency.FlaggedAsWaiting {
+		fmt.Println("Flagged time in WIP statuses counted as waiting")
+	}
+	fmt.Printf("%-15s %6s %10s %10s %10s\n", "Type", "Count", "Active", "Cycle", "Efficiency")
+	for _, typeEfficiency := range flowEfficiency.ByType {
+		fmt.Printf("%-15s %6d %10.1f %10.1f", typeEfficiency.IssueType, typeEfficiency.Issues,
+			typeEfficiency.Active.Days, typeEfficiency.Total.Days)
+		warn(" %9.1f%%\n", typeEfficiency.Efficiency)
+	}
+
+	fmt.Printf("\nDistribution:\n")
+	maxCount := 0
+	for _, bucket := range flowEfficiency.Distribution {
+		if bucket.Count > maxCount {
+			maxCount = bucket.Count
+		}
+	}
+	scale := 1.0
+	if maxCount > flowEfficiencyHistogramWidth {
+		scale = float64(flowEfficiencyHistogramWidth) / float64(maxCount)
+	}
+	for _, bucket := range flowEfficiency.Distribution {
+		fmt.Printf("%3d-%3d%% ", bucket.From, bucket.To)
+		warn("%-*s", flowEfficiencyHistogramWidth, strings.Repeat("#", int(math.Round(float64(bucket.Count)*scale))))
+		fmt.Printf(" %d\n", bucket.Count)
+	}
+}
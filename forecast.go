@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Upper bound of simulated working days when forecasting the completion date of a number of items
+const maxForecastDays = 5 * 260
+
+type ItemsForecast struct {
+	Confidence int
+	Items      int
+}
+
+// DateForecast has no Date when the items were not done within maxForecastDays working days
+type DateForecast struct {
+	Confidence  int
+	Date        *time.Time `json:",omitempty"`
+	WorkingDays int
+}
+
+type Forecast struct {
+	Iterations     int
+	HistoryDays    int
+	DailyAverage   float64
+	StartDate      time.Time
+	Until          *time.Time      `json:",omitempty"`
+	ItemsUntil     []ItemsForecast `json:",omitempty"`
+	Items          int             `json:",omitempty"`
+	ItemsDoneDates []DateForecast  `json:",omitempty"`
+}
+
+// Daily throughput for every working day of the period, based on the resolution date of the issues. Issues
+// resolved on a weekend or holiday count on the next working day, or on the last one at the end of the period.
+func getDailyThroughput(issueDetails []IssueDetails, startDate, endDate time.Time) []int {
+	workingDays := getWorkingDays(startDate, endDate)
+	if len(workingDays) == 0 {
+		return nil
+	}
+
+	dailyThroughput := make([]int, len(workingDays))
+	for _, issueDetails := range issueDetails {
+		if issueDetails.ResolvedDate.IsZero() {
+			continue
+		}
+		resolvedDay := parseDate(formatBrDate(issueDetails.ResolvedDate))
+		if resolvedDay.Before(startDate) || resolvedDay.After(endDate) {
+			continue
+		}
+		day := sort.Search(len(workingDays), func(i int) bool {
+			return !workingDays[i].Before(resolvedDay)
+		})
+		if day == len(workingDays) {
+			day--
+		}
+		dailyThroughput[day]++
+	}
+	return dailyThroughput
+}
+
+// Runs Monte Carlo simulations sampling the historical daily throughput, starting on the day after endDate
+func getForecast(issueDetails []IssueDetails, startDate, endDate time.Time) Forecast {
+	dailyThroughput := getDailyThroughput(issueDetails, startDate, endDate)
+	iterations := parsePositiveInt("--iterations", CLParameters.Iterations)
+	confidenceLevels := parseConfidenceLevels(CLParameters.Confidence)
+
+	var totalThroughput int
+	for _, throughput := range dailyThroughput {
+		totalThroughput += throughput
+	}
+	if totalThroughput == 0 {
+		log.Fatalf("No issues were resolved between %v and %v, there is no throughput to forecast from",
+			CLParameters.StartDate, CLParameters.EndDate)
+	}
+
+	forecast := Forecast{
+		Iterations:   iterations,
+		HistoryDays:  len(dailyThroughput),
+		DailyAverage: roundFloat(float64(totalThroughput) / float64(len(dailyThroughput))),
+		StartDate:    endDate.AddDate(0, 0, 1),
+	}
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	if CLParameters.Until != "" {
+		until := parseDate(CLParameters.Until)
+		if until.IsZero() || until.Before(forecast.StartDate) {
+			log.Fatalf("Invalid --until date %v, it must be after %v", CLParameters.Until, CLParameters.EndDate)
+		}
+		forecast.Until = &until
+		days := countWorkingDays(forecast.StartDate, until)
+
+		simulations := make([]int, iterations)
+		for i := range simulations {
+			for day := 0; day < days; day++ {
+				simulations[i] += dailyThroughput[random.Intn(len(dailyThroughput))]
+			}
+		}
+		sort.Ints(simulations)
+		for _, confidence := range confidenceLevels {
+			// at least this many items were done in confidence% of the simulations
+			forecast.ItemsUntil = append(forecast.ItemsUntil, ItemsForecast{
+				Confidence: confidence,
+				Items:      getIntPercentile(simulations, 100-confidence),
+			})
+		}
+	}
+
+	if CLParameters.Items != "" {
+		forecast.Items = parsePositiveInt("--items", CLParameters.Items)
+
+		// simulations not done within maxForecastDays working days stop one day later
+		simulations := make([]int, iterations)
+		for i := range simulations {
+			var done int
+			for done < forecast.Items && simulations[i] <= maxForecastDays {
+				done += dailyThroughput[random.Intn(len(dailyThroughput))]
+				simulations[i]++
+			}
+		}
+		sort.Ints(simulations)
+		forecastDays := getNextWorkingDays(forecast.StartDate, simulations[len(simulations)-1])
+		for _, confidence := range confidenceLevels {
+			dateForecast := DateForecast{
+				Confidence:  confidence,
+				WorkingDays: getIntPercentile(simulations, confidence),
+			}
+			if dateForecast.WorkingDays <= maxForecastDays {
+				dateForecast.Date = &forecastDays[dateForecast.WorkingDays-1]
+			} else {
+				dateForecast.WorkingDays = maxForecastDays
+			}
+			forecast.ItemsDoneDates = append(forecast.ItemsDoneDates, dateForecast)
+		}
+	}
+	return forecast
+}
+
+func parsePositiveInt(option string, value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Fatalf("Invalid %v value %v, it must be a positive number", option, value)
+	}
+	return n
+}
+
+func parseConfidenceLevels(value string) []int {
+	var confidenceLevels []int
+	for _, level := range strings.Split(value, ",") {
+		confidence, err := strconv.Atoi(strings.TrimSpace(level))
+		if err != nil || confidence <= 0 || confidence >= 100 {
+			log.Fatalf("Invalid confidence level %v, it must be between 1 and 99", level)
+		}
+		confidenceLevels = append(confidenceLevels, confidence)
+	}
+	return confidenceLevels
+}
+
+func writeForecast(forecast Forecast) {
+	switch CLParameters.Format {
+	case "text":
+		printForecast(forecast)
+	case "json":
+		withOutput(func(output io.Writer) {
+			writeJSON(output, forecast, "forecast")
+		})
+	default:
+		log.Fatalf("Unsupported output format for forecast: %v", CLParameters.Format)
+	}
+}
+
+func printForecast(forecast Forecast) {
+	title("Monte Carlo forecast of project %s // ", BoardCfg.Project)
+	title("Throughput from %s to %s\n", CLParameters.StartDate, CLParameters.EndDate)
+	fmt.Printf("%d simulations over %d working days of history, ", forecast.Iterations, forecast.HistoryDays)
+	warn("%.2f tasks delivered per day\n", forecast.DailyAverage)
+
+	if forecast.Until != nil {
+		title("\n> How many tasks will be done from %s to %s\n", formatBrDate(forecast.StartDate), formatBrDate(*forecast.Until))
+		for _, itemsForecast := range forecast.ItemsUntil {
+			fmt.Printf("%d%%: ", itemsForecast.Confidence)
+			warn("%d tasks or more\n", itemsForecast.Items)
+		}
+	}
+
+	if forecast.Items > 0 {
+		title("\n> When will %d tasks be done starting on %s\n", forecast.Items, formatBrDate(forecast.StartDate))
+		for _, dateForecast := range forecast.ItemsDoneDates {
+			fmt.Printf("%d%%: ", dateForecast.Confidence)
+			if dateForecast.Date == nil {
+				warn("not reached within %d working days\n", dateForecast.WorkingDays)
+				continue
+			}
+			warn("%s", formatBrDate(*dateForecast.Date))
+			fmt.Printf(" (%d working days)\n", dateForecast.WorkingDays)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func newResolvedIssues(days ...int) []IssueDetails {
+	var issues []IssueDetails
+	for _, day := range days {
+		issues = append(issues, newTestIssue("PRJ-1", "Story", testTime(day, 3, 8),
+			testTransition{testTime(day, 3, 9), "In Progress"}, testTransition{testTime(day, 3, 17), "Done"}))
+	}
+	return issues
+}
+
+func TestGetDailyThroughput(t *testing.T) {
+	useTestBoard()
+	Calendar.addHolidays(testTime(10, 3, 0), testTime(10, 3, 0))
+
+	// resolved on Friday, Saturday, Sunday, a Tuesday holiday and the Sunday ending the period
+	issues := newResolvedIssues(6, 7, 8, 10, 15)
+	got := getDailyThroughput(issues, testTime(2, 3, 0), testTime(15, 3, 0))
+	want := []int{0, 0, 0, 0, 1, 2, 1, 0, 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getDailyThroughput = %v, want %v", got, want)
+	}
+}
+
+func TestForecastMaxDays(t *testing.T) {
+	useTestBoard()
+	CLParameters.Iterations = "10"
+	CLParameters.Confidence = "50,95"
+	defer func() { CLParameters.Iterations, CLParameters.Confidence, CLParameters.Items = "", "", "" }()
+	// one task every working day
+	issues := newResolvedIssues(2, 3, 4, 5, 6)
+
+	tests := []struct {
+		items   int
+		reached bool
+	}{
+		{maxForecastDays, true},
+		{maxForecastDays + 1, false},
+	}
+	for _, test := range tests {
+		CLParameters.Items = fmt.Sprint(test.items)
+		forecast := getForecast(issues, testTime(2, 3, 0), testTime(6, 3, 0))
+		for _, dateForecast := range forecast.ItemsDoneDates {
+			if (dateForecast.Date != nil) != test.reached || dateForecast.WorkingDays != maxForecastDays {
+				t.Errorf("%d items: %d%% done in %d working days on %v, want reached %v in %d working days",
+					test.items, dateForecast.Confidence, dateForecast.WorkingDays, dateForecast.Date, test.reached,
+					maxForecastDays)
+			}
+		}
+	}
+}
@@ -1,6 +1,6 @@
 module jira-kanban-metrics
 
-go 1.12
+go 1.13
 
 require (
 	github.com/andygrunwald/go-jira v1.10.0
@@ -8,4 +8,5 @@ require (
 	github.com/hako/durafmt v0.0.0-20190612201238-650ed9f29a84
 	github.com/mattn/go-colorable v0.1.2 // indirect
 	github.com/zchee/color v1.7.0
+	golang.org/x/term v0.0.0-20201210144234-2321bbc49cbf
 )
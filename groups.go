@@ -0,0 +1,153 @@
+package main
+
+import (
+	"log"
+	"strings"
+)
+
+// Dimensions accepted by --group-by and --filter besides the configured custom fields
+var groupDimensions = []string{"type", "epic", "label", "assignee", "component", "priority", "sprint"}
+
+// Group of the issues without a value for the dimension
+const noGroupValue = "None"
+
+// GroupMetrics holds the sections of the report computed for the issues of one group
+type GroupMetrics struct {
+	Group               string
+	Issues              int
+	Throughput          int
+	CycleTime           ReportDuration
+	Wip                 WipSummary
+	AverageByStatus     []StatusDuration
+	AverageByStatusType []StatusDuration
+}
+
+// Accepts a built in dimension or the name or ID of a configured custom field,
+// including fields configured by name whose ID is only resolved later from Jira
+func checkDimension(option string, dimension string) {
+	if containsIgnoringCase(groupDimensions, dimension) {
+		return
+	}
+	for _, cfg := range getCustomFieldsCfg() {
+		if strings.ToUpper(cfg.Name) == strings.ToUpper(dimension) || (cfg.Id != "" && cfg.Id == dimension) {
+			return
+		}
+	}
+	log.Fatalf("Invalid %v %v, expected one of %v or a custom field of the board config",
+		option, dimension, strings.Join(groupDimensions, ", "))
+}
+
+// Returns the values of the issue for the dimension, multi valued dimensions such as labels returning several
+func getDimensionValues(issueDetails IssueDetails, dimension string) []string {
+	var values []string
+	switch strings.ToLower(dimension) {
+	case "type":
+		values = []string{issueDetails.IssueType}
+	case "epic":
+		values = []string{issueDetails.EpicLink}
+	case "label":
+		values = issueDetails.Labels
+	case "assignee":
+		values = []string{issueDetails.Assignee}
+	case "component":
+		values = issueDetails.Components
+	case "priority":
+		values = []string{issueDetails.Priority}
+	case "sprint":
+		for _, customField := range issueDetails.CustomFields {
+			if sprint, ok := customField.(SprintCustomField); ok && sprint.SprintName != "" {
+				values = append(values, sprint.SprintName)
+			}
+		}
+		if len(values) == 0 {
+			values = []string{issueDetails.Sprint}
+		}
+	default:
+		values = getCustomFieldValues(issueDetails.CustomFields, dimension)
+	}
+
+	var nonEmpty []string
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return nonEmpty
+}
+
+// Splits the issues by the values of the dimension, an issue with several values being in each of their groups
+func getIssueDetailsMapByDimension(issueDetails []IssueDetails, dimension string) map[string][]IssueDetails {
+	issueDetailsByGroup := make(map[string][]IssueDetails)
+	for _, issueDetail := range issueDetails {
+		values := getDimensionValues(issueDetail, dimension)
+		if len(values) == 0 {
+			values = []string{noGroupValue}
+		}
+		for _, value := range values {
+			issueDetailsByGroup[value] = append(issueDetailsByGroup[value], issueDetail)
+		}
+	}
+	return issueDetailsByGroup
+}
+
+func getGroups(issueDetails []IssueDetails, dimension string, workingDays int) []GroupMetrics {
+	var groups []GroupMetrics
+	issueDetailsByGroup := getIssueDetailsMapByDimension(issueDetails, dimension)
+	for _, group := range getSortedIssueTypes(issueDetailsByGroup) {
+		groupIssues := issueDetailsByGroup[group]
+		groups = append(groups, GroupMetrics{
+			Group:               group,
+			Issues:              len(groupIssues),
+			Throughput:          getThroughput(groupIssues).Total,
+			CycleTime:           getAverageCycleTime(getIssueDetailsMapByType(groupIssues)).Average,
+			Wip:                 getWIP(groupIssues, workingDays),
+			AverageByStatus:     getAverageByStatus(groupIssues),
+			AverageByStatusType: getAverageByStatusType(groupIssues),
+		})
+	}
+	return groups
+}
+
+type issueFilter struct {
+	dimension string
+	value     string
+}
+
+// Parses --filter, a comma separated list of dimension=value conditions
+func parseFilters(filters string) []issueFilter {
+	var parsed []issueFilter
+	if filters == "" {
+		return parsed
+	}
+	for _, filter := range strings.Split(filters, ",") {
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			log.Fatalf("Invalid --filter %v, expected dimension=value", filter)
+		}
+		dimension := strings.TrimSpace(parts[0])
+		checkDimension("--filter", dimension)
+		parsed = append(parsed, issueFilter{dimension: dimension, value: strings.TrimSpace(parts[1])})
+	}
+	return parsed
+}
+
+// Keeps the issues matching every filter, a multi valued dimension matching when any of its values does
+func filterIssueDetails(issueDetails []IssueDetails, filters []issueFilter) []IssueDetails {
+	if len(filters) == 0 {
+		return issueDetails
+	}
+	var filtered []IssueDetails
+	for _, issueDetail := range issueDetails {
+		matches := true
+		for _, filter := range filters {
+			matches = matches && containsIgnoringCase(getDimensionValues(issueDetail, filter.dimension), filter.value)
+		}
+		if matches {
+			filtered = append(filtered, issueDetail)
+		}
+	}
+	if CLParameters.Debug {
+		log.Printf("%d of %d issues match --filter %v", len(filtered), len(issueDetails), CLParameters.Filter)
+	}
+	return filtered
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+type agileBoardConfiguration struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Location struct {
+		Type string `json:"type"`
+		Key  string `json:"key"`
+	} `json:"location"`
+	ColumnConfig struct {
+		Columns []agileBoardColumn `json:"columns"`
+	} `json:"columnConfig"`
+}
+
+type agileBoardColumn struct {
+	Name     string `json:"name"`
+	Statuses []struct {
+		Id string `json:"id"`
+	} `json:"statuses"`
+}
+
+// Board config written by init, holding the Jira connection settings and the status mapping
+type initBoardCfg struct {
+	JiraUrl       string
+	AuthType      string `json:",omitempty"`
+	Login         string
+	SecretEnv     string  `json:",omitempty"`
+	SecretCommand string  `json:",omitempty"`
+	TLS           *TLSCfg `json:",omitempty"`
+	Proxy         string  `json:",omitempty"`
+	Project       string
+	OpenStatus    []string
+	WipStatus     []string
+	IdleStatus    []string
+	DoneStatus    []string
+}
+
+var columnStatusTypes = []string{"open", "wip", "idle", "done"}
+
+// Reads the column configuration of an agile board and writes a board config,
+// asking how each column should be classified
+func createBoardCfg() {
+	outputFile := CLParameters.Output
+	if outputFile == "" {
+		outputFile = configFile
+	}
+	if _, err := os.Stat(outputFile); err == nil {
+		log.Fatalf("%v already exists, choose another file with --output", outputFile)
+	}
+
+	input := bufio.NewReader(os.Stdin)
+	loadConnectionCfg(input)
+	authJiraClient()
+	loadJiraStatuses()
+
+	board := getAgileBoardConfiguration(CLParameters.BoardId)
+	title("Board %v\n", board.Name)
+
+	cfg := initBoardCfg{
+		JiraUrl:       BoardCfg.JiraUrl,
+		AuthType:      BoardCfg.AuthType,
+		Login:         BoardCfg.Login,
+		SecretEnv:     BoardCfg.SecretEnv,
+		SecretCommand: BoardCfg.SecretCommand,
+		Proxy:         BoardCfg.Proxy,
+		Project:       board.Location.Key,
+		OpenStatus:    []string{},
+		WipStatus:     []string{},
+		IdleStatus:    []string{},
+		DoneStatus:    []string{},
+	}
+	if BoardCfg.TLS != (TLSCfg{}) {
+		cfg.TLS = &BoardCfg.TLS
+	}
+	if cfg.Project == "" {
+		cfg.Project = prompt(input, "Project key", "")
+	}
+
+	columns := board.ColumnConfig.Columns
+	for i, column := range columns {
+		// the config gets the status IDs, which keep matching when a status is renamed
+		var statuses []string
+		var descriptions []string
+		var categories []string
+		for _, status := range column.Statuses {
+			statuses = append(statuses, status.Id)
+			if known, found := knownStatusesById[status.Id]; found {
+				descriptions = append(descriptions, fmt.Sprintf("%v: %v", known.Id, known.Name))
+				categories = append(categories, known.Category)
+			} else {
+				descriptions = append(descriptions, status.Id)
+			}
+		}
+		if len(statuses) == 0 {
+			continue
+		}
+
+		question := fmt.Sprintf("Column %v (%v) is %v", column.Name, strings.Join(descriptions, ", "), strings.Join(columnStatusTypes, "/"))
+		statusType := promptColumnStatusType(input, question, suggestColumnStatusType(i, len(columns), categories))
+		switch statusType {
+		case "open":
+			cfg.OpenStatus = append(cfg.OpenStatus, statuses...)
+		case "wip":
+			cfg.WipStatus = append(cfg.WipStatus, statuses...)
+		case "idle":
+			cfg.IdleStatus = append(cfg.IdleStatus, statuses...)
+		case "done":
+			cfg.DoneStatus = append(cfg.DoneStatus, statuses...)
+		}
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		log.Fatalf("Failed to encode board config: %v", err)
+	}
+	err = ioutil.WriteFile(outputFile, append(data, '\n'), 0600)
+	if err != nil {
+		log.Fatalf("Failed to write config file %v: %v", outputFile, err)
+	}
+	info("Board config written to %v\n", outputFile)
+}
+
+// Takes the Jira connection settings from the config file when there is one, otherwise asks for them
+func loadConnectionCfg(input *bufio.Reader) {
+	if path, _ := lookupConfigFile(); path != "" {
+		readBoardCfg(CLParameters.Board)
+		return
+	}
+	BoardCfg.JiraUrl = prompt(input, "Jira URL", "")
+	BoardCfg.Login = prompt(input, "Login (empty for anonymous access)", "")
+}
+
+func getAgileBoardConfiguration(boardId string) agileBoardConfiguration {
+	var board agileBoardConfiguration
+	u := fmt.Sprintf("rest/agile/1.0/board/%s/configuration", boardId)
+	req, err := JiraClient.NewRequest("GET", u, nil)
+	if err == nil {
+		err = callJira("board configuration", func() (*jira.Response, error) {
+			resp, err := JiraClient.Do(req, &board)
+			if err != nil {
+				return resp, jira.NewJiraError(resp, err)
+			}
+			return resp, nil
+		})
+	}
+	if err != nil {
+		log.Fatalf("Failed to get configuration of board %v from jira: %v", boardId, err)
+	}
+	return board
+}
+
+// Suggests done for columns whose statuses are all in the Done category, open for the To Do category
+// or the first column, and wip otherwise
+func suggestColumnStatusType(column int, columns int, categories []string) string {
+	if len(categories) > 0 && allEqual(categories, doneStatusCategory) {
+		return "done"
+	}
+	if column == 0 || (len(categories) > 0 && allEqual(categories, toDoStatusCategory)) {
+		return "open"
+	}
+	if column == columns-1 {
+		return "done"
+	}
+	return "wip"
+}
+
+func allEqual(values []string, value string) bool {
+	for _, v := range values {
+		if v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func promptColumnStatusType(input *bufio.Reader, question string, suggestion string) string {
+	for {
+		answer := strings.ToLower(prompt(input, question, suggestion))
+		for _, statusType := range columnStatusTypes {
+			if answer == statusType || answer == statusType[:1] {
+				return statusType
+			}
+		}
+		warn("Answer one of %v\n", strings.Join(columnStatusTypes, ", "))
+	}
+}
+
+func prompt(input *bufio.Reader, question string, suggestion string) string {
+	if suggestion != "" {
+		_, _ = fmt.Fprintf(os.Stderr, "%v [%v]: ", question, suggestion)
+	} else {
+		_, _ = fmt.Fprintf(os.Stderr, "%v: ", question)
+	}
+	answer, err := input.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		log.Fatalf("Failed to read answer: %v", err)
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return suggestion
+	}
+	return answer
+}
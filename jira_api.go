@@ -1,28 +1,24 @@
 package main
 
 import (
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"github.com/andygrunwald/go-jira"
-	"github.com/zchee/color"
 	"log"
-	"net/http"
-	"strings"
+	"net/url"
+	"sync"
 	"time"
 )
 
 var JiraClient jira.Client
 
+// Size of the goroutine pool of runConcurrently, parsed from --workers at startup
+var jiraWorkers int
+
 func authJiraClient() {
-	tp := jira.BasicAuthTransport{
-		Username: strings.TrimSpace(BoardCfg.Login),
-		Password: strings.TrimSpace(BoardCfg.Password),
-		// ignore missing certs
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
-	client, err := jira.NewClient(tp.Client(), BoardCfg.JiraUrl)
+	httpClient := getAuthClient(getTransport())
+	httpClient.Timeout = time.Duration(parsePositiveInt("--timeout", CLParameters.Timeout)) * time.Second
+	client, err := jira.NewClient(httpClient, BoardCfg.JiraUrl)
 	if err != nil {
 		panic(err)
 	}
@@ -30,128 +26,151 @@ func authJiraClient() {
 	JiraClient = *client
 }
 
-const issuesJql = "project = '%v' AND issuetype != Epic AND status CHANGED DURING('%v', '%v') ORDER BY status"
+const issuesJql = "project = '%v'%v AND issuetype != Epic AND status CHANGED DURING('%v', '%v') ORDER BY status"
 
 func getIssuesJqlSearch() string {
-	jqlSearch := fmt.Sprintf(issuesJql, BoardCfg.Project, formatJiraDate(parseDate(CLParameters.StartDate)), formatJiraDate(parseDate(CLParameters.EndDate)))
-	if CLParameters.Debug {
-		title("JQL: %s\n", jqlSearch)
-	}
+	jqlSearch := fmt.Sprintf(issuesJql, BoardCfg.Project, getBoardJqlFilter(), formatJiraDate(parseDate(CLParameters.StartDate)), formatJiraDate(parseDate(CLParameters.EndDate)))
 	return jqlSearch
 }
 
-func searchIssues(jql string) []jira.Issue {
-	if CLParameters.Debug {
-		log.Printf("JQL: %v", jql)
-	}
-	var i = 0
-	var issues []jira.Issue
-	searchOptions := jira.SearchOptions{MaxResults: 100, Expand: "changelog"}
-	for {
-		searchOptions.StartAt = i
-		res, resp, err := JiraClient.Issue.Search(jql, &searchOptions)
-		if err != nil {
-			log.Fatalf("Failed to search issues on jira: %v\nResponse body: %v", err, readResponseBody(resp))
-		}
-		issues = append(issues, res...)
-		i += resp.MaxResults
-		if i >= resp.Total {
-			break
-		}
+// Extra clause from the board config to narrow down the project issues
+func getBoardJqlFilter() string {
+	if BoardCfg.Jql == "" {
+		return ""
 	}
-	if CLParameters.Debug {
-		log.Printf("Total issues returned: %v", len(issues))
-	}
-	return issues
+	return fmt.Sprintf(" AND (%v)", BoardCfg.Jql)
 }
 
-type CustomField interface {
-	Id() string
-	String() string
-	Unmarshall(interface{}) CustomField
-}
-
-type SprintCustomField struct {
-	Name         string
-	State        string
-	StartDate    time.Time
-	EndDate      time.Time
-	CompleteDate time.Time
-}
+const searchPageSize = 100
 
-func (SprintCustomField) Id() string {
-	return "customfield_10021"
+type searchResult struct {
+	StartAt    int          `json:"startAt"`
+	MaxResults int          `json:"maxResults"`
+	Total      int          `json:"total"`
+	Issues     []jira.Issue `json:"issues"`
+	// Total of changelog histories of each issue, which go-jira does not expose
+	ChangelogTotals []int `json:"-"`
 }
 
-func (cf SprintCustomField) String() string {
-	return color.CyanString(cf.Name)
+type searchChangelogTotals struct {
+	Issues []struct {
+		Changelog struct {
+			Total int `json:"total"`
+		} `json:"changelog"`
+	} `json:"issues"`
 }
 
-func (SprintCustomField) Unmarshall(data interface{}) CustomField {
-	cf := SprintCustomField{}
-	m := data.(map[string]interface{})
-	if name, ok := m["name"].(string); ok {
-		cf.Name = name
-	}
-	if state, ok := m["state"].(string); ok {
-		cf.State = state
+// Fetches the first page to learn the total of issues, then the remaining pages concurrently
+// with at most --workers requests at a time, keeping the issues in the order returned by Jira
+func searchIssues(jql string) []jira.Issue {
+	if CLParameters.Debug {
+		log.Printf("JQL: %v", jql)
 	}
-	if startDateStr, ok := m["startDate"].(string); ok {
-		if startDate, err := time.Parse(time.RFC3339, startDateStr); err == nil {
-			cf.StartDate = startDate
-		}
+	firstPage, err := searchPage(jql, 0)
+	if err != nil {
+		log.Fatalf("Failed to search issues on jira: %v\nJQL: %v", err, jql)
 	}
-	if endDateStr, ok := m["endDate"].(string); ok {
-		if endDate, err := time.Parse(time.RFC3339, endDateStr); err == nil {
-			cf.EndDate = endDate
-		}
+
+	pageSize := firstPage.MaxResults
+	if pageSize <= 0 {
+		pageSize = len(firstPage.Issues)
 	}
-	if completedDateStr, ok := m["endDate"].(string); ok {
-		if completedDate, err := time.Parse(time.RFC3339, completedDateStr); err == nil {
-			cf.CompleteDate = completedDate
-		}
+	var pageOffsets []int
+	for startAt := pageSize; pageSize > 0 && startAt < firstPage.Total; startAt += pageSize {
+		pageOffsets = append(pageOffsets, startAt)
 	}
-	return cf
-}
 
-type FlagCustomField struct {
-	Value string
-}
+	progress := newProgressBar("Fetching issues", firstPage.Total)
+	progress.add(len(firstPage.Issues))
+	pages := make([]searchResult, len(pageOffsets))
+	err = runConcurrently(len(pageOffsets), func(page int) error {
+		var err error
+		pages[page], err = searchPage(jql, pageOffsets[page])
+		progress.add(len(pages[page].Issues))
+		return err
+	})
+	progress.finish()
+	if err != nil {
+		log.Fatalf("Failed to search issues on jira: %v", err)
+	}
 
-func (FlagCustomField) Id() string {
-	return "customfield_10035"
-}
+	issues, changelogTotals := firstPage.Issues, firstPage.ChangelogTotals
+	for _, page := range pages {
+		issues = append(issues, page.Issues...)
+		changelogTotals = append(changelogTotals, page.ChangelogTotals...)
+	}
+	completeChangelogs(issues, changelogTotals)
 
-func (cf FlagCustomField) String() string {
-	return color.RedString("Flag")
+	if CLParameters.Debug {
+		log.Printf("Total issues returned: %v", len(issues))
+	}
+	return issues
 }
 
-func (FlagCustomField) Unmarshall(data interface{}) CustomField {
-	cf := FlagCustomField{}
-	m := data.(map[string]interface{})
-	if value, ok := m["value"].(string); ok {
-		cf.Value = value
+func searchPage(jql string, startAt int) (searchResult, error) {
+	var result searchResult
+	u := fmt.Sprintf("rest/api/2/search?jql=%s&startAt=%d&maxResults=%d&expand=changelog",
+		url.QueryEscape(jql), startAt, searchPageSize)
+	req, err := JiraClient.NewRequest("GET", u, nil)
+	if err != nil {
+		return result, err
 	}
-	return cf
+
+	err = callJira(fmt.Sprintf("search page starting at %v", startAt), func() (*jira.Response, error) {
+		var body json.RawMessage
+		resp, err := JiraClient.Do(req, &body)
+		if err != nil {
+			return resp, jira.NewJiraError(resp, err)
+		}
+		var changelogTotals searchChangelogTotals
+		if err := json.Unmarshal(body, &result); err != nil {
+			return resp, err
+		}
+		if err := json.Unmarshal(body, &changelogTotals); err != nil {
+			return resp, err
+		}
+		result.ChangelogTotals = nil
+		for _, issue := range changelogTotals.Issues {
+			result.ChangelogTotals = append(result.ChangelogTotals, issue.Changelog.Total)
+		}
+		return resp, nil
+	})
+	return result, err
 }
 
-var supportedCustomFields = []CustomField{SprintCustomField{}, FlagCustomField{}}
-
-func getCustomFields(issue jira.Issue) []CustomField {
-	var customFields []CustomField
-	for name, value := range issue.Fields.Unknowns {
-		for _, supportedCustomField := range supportedCustomFields {
-			if name == supportedCustomField.Id() && value != nil {
-				switch customField := value.(type) {
-				case []interface{}:
-					for _, field := range customField {
-						customFields = append(customFields, supportedCustomField.Unmarshall(field))
-					}
-				case interface{}:
-					customFields = append(customFields, supportedCustomField.Unmarshall(customField))
+// Runs job for every index from 0 to count-1 with a bounded pool of jiraWorkers goroutines,
+// stopping to dispatch new jobs at the first error
+func runConcurrently(count int, job func(int) error) error {
+	jobs := make(chan int)
+	var firstErr error
+	var errOnce sync.Once
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for w := 0; w < jiraWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := job(i); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						close(done)
+					})
 				}
 			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < count; i++ {
+		select {
+		case jobs <- i:
+		case <-done:
+			break dispatch
 		}
 	}
-	return customFields
+	close(jobs)
+	wg.Wait()
+	return firstErr
 }
@@ -2,26 +2,142 @@ package main
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
 const configFile = "jira_board.cfg"
+const configEnvVar = "JIRA_KANBAN_METRICS_CONFIG"
+
+// Path of the loaded config file, relative paths inside it are resolved from its directory
+var configPath string
 
 func loadBoardCfg() {
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		panic(configFile + " not found")
+	readBoardCfg(CLParameters.Board)
+	if problems := validateBoardCfg(); len(problems) > 0 {
+		log.Fatalf("Invalid config file %v:\n%v", configPath, strings.Join(problems, "\n"))
 	}
+	loadCalendar()
+	loadCustomFields()
+}
+
+// Decodes the config file and applies the settings of the given board, without validating them
+func readBoardCfg(board string) {
+	decodeConfigFile()
+	selectBoard(board)
+}
+
+func decodeConfigFile() {
+	configPath = findConfigFile()
 
-	file, err := os.Open(configFile)
-	defer file.Close()
+	data, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		log.Fatalf("Failed to open config file %v: %v", configFile, err)
+		log.Fatalf("Failed to open config file %v: %v", configPath, err)
+	}
+
+	BoardCfg = BoardConfig{}
+	err = json.Unmarshal(data, &BoardCfg)
+	if err != nil {
+		log.Fatalf("Failed to decode config file %v: %v", configPath, err)
+	}
+}
+
+func findConfigFile() string {
+	path, searched := lookupConfigFile()
+	if path == "" {
+		log.Fatalf("%v not found, searched: %v", configFile, strings.Join(searched, ", "))
+	}
+	return path
+}
+
+// Looks for the config file in the --config flag, the JIRA_KANBAN_METRICS_CONFIG environment variable,
+// the XDG config directory and the current directory, in this order.
+// Returns an empty path and the searched locations when it is not found.
+func lookupConfigFile() (string, []string) {
+	if CLParameters.Config != "" {
+		return CLParameters.Config, nil
+	}
+	if envConfig := os.Getenv(configEnvVar); envConfig != "" {
+		return envConfig, nil
+	}
+
+	var searched []string
+	if configDir := getXDGConfigDir(); configDir != "" {
+		xdgConfig := filepath.Join(configDir, "jira-kanban-metrics", configFile)
+		if _, err := os.Stat(xdgConfig); err == nil {
+			return xdgConfig, nil
+		}
+		searched = append(searched, xdgConfig)
+	}
+	if _, err := os.Stat(configFile); err == nil {
+		return configFile, nil
+	}
+	searched = append(searched, configFile)
+	return "", searched
+}
+
+func getXDGConfigDir() string {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return configHome
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		return filepath.Join(home, ".config")
+	}
+	return ""
+}
+
+// Applies the settings of the board chosen with --board (or DefaultBoard) over the top level ones
+func selectBoard(board string) {
+	if len(BoardCfg.Boards) == 0 {
+		if board != "" {
+			log.Fatalf("Board %v not found, %v has no Boards section", board, configPath)
+		}
+		return
+	}
+
+	if board == "" {
+		board = BoardCfg.DefaultBoard
+	}
+	if board == "" && len(BoardCfg.Boards) == 1 {
+		for name := range BoardCfg.Boards {
+			board = name
+		}
+	}
+	if board == "" {
+		if BoardCfg.Project != "" {
+			return
+		}
+		log.Fatalf("Choose a board with --board: %v", strings.Join(getBoardNames(), ", "))
 	}
 
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&BoardCfg)
+	boardData, found := BoardCfg.Boards[board]
+	if !found {
+		log.Fatalf("Board %v not found in %v, available boards: %v", board, configPath, strings.Join(getBoardNames(), ", "))
+	}
+	err := json.Unmarshal(boardData, &BoardCfg)
 	if err != nil {
-		log.Fatalf("Failed to decode config file %v: %v", configFile, err)
+		log.Fatalf("Failed to decode board %v in config file %v: %v", board, configPath, err)
+	}
+	BoardCfg.Board = board
+}
+
+func getBoardNames() []string {
+	var names []string
+	for name := range BoardCfg.Boards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves a path found in the config file relative to the config file directory
+func getConfigRelativePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
 	}
+	return filepath.Join(filepath.Dir(configPath), path)
 }
@@ -22,16 +22,25 @@ import (
 	"fmt"
 	"github.com/andygrunwald/go-jira"
 	"github.com/docopt/docopt-go"
+	"github.com/zchee/color"
 	"log"
 	"sort"
+	"strings"
 	"time"
 )
 
 var usage = `Jira kanban metrics
 
 Usage: 
-  jira-kanban-metrics <start> <end> [--debug]
-  jira-kanban-metrics <JQL> [--debug]
+  jira-kanban-metrics cache (status | clear) [options]
+  jira-kanban-metrics statuses [options]
+  jira-kanban-metrics init --board-id=<id> [options]
+  jira-kanban-metrics validate [options]
+  jira-kanban-metrics <start> <end> [options]
+  jira-kanban-metrics cfd <start> <end> [options]
+  jira-kanban-metrics aging <start> <end> [options]
+  jira-kanban-metrics forecast <start> <end> [--items=<n>] [--until=<date>] [options]
+  jira-kanban-metrics <JQL> [options]
   jira-kanban-metrics -h | --help
   jira-kanban-metrics --version
 
@@ -41,9 +50,29 @@ Arguments:
   JQL    The jql.
 
 Options:
-  --debug    Print debug output.
-  -h --help  Show this screen.
-  --version  Show version.
+  --config=<file>         Board config file, see README for the lookup order.
+  --board=<name>          Board of the config file to use.
+  --board-id=<id>         Id of the Jira agile board to create the config from.
+  --workers=<n>           Number of concurrent Jira search requests [default: 4].
+  --retries=<n>           Retries of Jira requests failing with transient errors [default: 5].
+  --timeout=<seconds>     Timeout of each Jira request [default: 60].
+  --cache                 Sync issues into a local cache and read them from it.
+  --save-snapshot=<file>  Save the issues returned by Jira to a snapshot file.
+  --from-snapshot=<file>  Read issues from a snapshot file instead of querying Jira.
+  --group-by=<dimension>  Also compute throughput, cycle time, status breakdown and WIP by type, epic,
+                          label, assignee, component, priority, sprint or a custom field.
+  --interval=<interval>   Throughput run chart interval: day, week or month [default: week].
+  --flagged-as-waiting    Count flagged time in WIP statuses as waiting time in the flow efficiency.
+  --filter=<conditions>   Only keep the issues matching every dimension=value condition, comma separated.
+  --format=<format>       Output format: text, json, csv or html [default: text].
+  --output=<file>         Write json, csv and html output, or the init config, to this file.
+  --items=<n>             Forecast when this number of tasks will be done.
+  --until=<date>          Forecast how many tasks will be done until this date (dd/mm/yyyy).
+  --iterations=<n>        Number of Monte Carlo simulations [default: 10000].
+  --confidence=<levels>   Comma separated forecast confidence levels [default: 50,85,95].
+  --debug                 Print debug output.
+  -h --help               Show this screen.
+  --version               Show version.
 `
 
 const version = "1.4"
@@ -55,43 +84,96 @@ func main() {
 		log.Fatalf("Failed to parse command line arguments: %v", err)
 	}
 
+	if CLParameters.Forecast && CLParameters.Items == "" && CLParameters.Until == "" {
+		log.Fatalf("Forecast needs --items, --until or both")
+	}
+
+	if CLParameters.Format != "text" {
+		color.NoColor = true
+	}
+
+	if CLParameters.Init {
+		createBoardCfg()
+		return
+	}
+
+	if CLParameters.Validate {
+		printValidation()
+		return
+	}
+
 	loadBoardCfg()
-	authJiraClient()
 
-	title("Extracting Kanban metrics from project %s // ", BoardCfg.Project)
-	title("From %s to %s\n", CLParameters.StartDate, CLParameters.EndDate)
+	if CLParameters.Cache {
+		if CLParameters.CacheStatus {
+			printCacheStatus()
+		} else if CLParameters.CacheClear {
+			clearCache()
+		}
+		return
+	}
 
-	var issues []jira.Issue
-	if CLParameters.Jql != "" {
-		issues = searchIssues(CLParameters.Jql)
-	} else {
-		issues = searchIssues(getIssuesJqlSearch())
+	if CLParameters.Statuses {
+		authJiraClient()
+		printStatuses()
+		return
 	}
 
+	if CLParameters.GroupBy != "" {
+		checkDimension("--group-by", CLParameters.GroupBy)
+	}
+	filters := parseFilters(CLParameters.Filter)
+	interval := parseInterval(CLParameters.Interval)
+	jiraWorkers = parsePositiveInt("--workers", CLParameters.Workers)
+
 	startDate, endDate := parseDate(CLParameters.StartDate), parseDate(CLParameters.EndDate)
 
-	issueDetails := getIssueDetailsList(issues, endDate)
+	var issues []jira.Issue
+	if CLParameters.FromSnapshot != "" {
+		issues = loadSnapshot(CLParameters.FromSnapshot)
+		warnUnresolvedCustomFields()
+	} else {
+		authJiraClient()
+		resolveCustomFields()
+		if BoardCfg.StatusCategoryFallback {
+			loadJiraStatuses()
+		}
+		if CLParameters.UseCache {
+			issues = getCachedIssues(startDate, endDate)
+		} else if CLParameters.Jql != "" {
+			issues = searchIssues(CLParameters.Jql)
+		} else if CLParameters.Aging {
+			if !BoardCfg.StatusCategoryFallback {
+				loadJiraStatuses()
+			}
+			issues = searchIssues(getAgingJqlSearch())
+		} else {
+			issues = searchIssues(getIssuesJqlSearch())
+		}
+	}
 
-	printNotMapped(issueDetails)
+	if CLParameters.SaveSnapshot != "" {
+		saveSnapshot(CLParameters.SaveSnapshot, issues)
+	}
+	addIssueStatuses(issues)
+	validateStatusIds()
 
-	byType := getIssueDetailsMapByType(issueDetails)
-	printIssueDetailsByType(byType)
-	printAverageByStatus(issueDetails)
-	printAverageByStatusType(issueDetails)
-	printWIP(issueDetails, countWeekDays(startDate, endDate))
-	printThroughput(issueDetails)
-	printLeadTime(byType)
-}
+	issueDetails := filterIssueDetails(getIssueDetailsList(issues, endDate), filters)
+	warnNotMapped(getNotMapped(issueDetails))
 
-func printNotMapped(issueDetails []IssueDetails) {
-	if CLParameters.Debug {
-		notMapped := getNotMapped(issueDetails)
-		if len(notMapped) > 0 {
-			warn("\nThe following status were found but not mapped in board.cfg:\n")
-			for status, _ := range notMapped {
-				fmt.Println(status)
-			}
+	if CLParameters.Cfd {
+		writeCumulativeFlow(getCumulativeFlow(issueDetails, startDate, endDate))
+	} else if CLParameters.Aging {
+		writeAging(getAging(issueDetails, endDate))
+	} else if CLParameters.Forecast {
+		writeForecast(getForecast(issueDetails, startDate, endDate))
+	} else {
+		// only the html report charts the cumulative flow
+		var cumulativeFlow CumulativeFlow
+		if CLParameters.Format == "html" && !startDate.IsZero() {
+			cumulativeFlow = getCumulativeFlow(issueDetails, startDate, endDate)
 		}
+		writeReport(buildReport(issueDetails, startDate, endDate, interval), cumulativeFlow)
 	}
 }
 
@@ -111,6 +193,15 @@ func getIssueDetailsList(issues []jira.Issue, endDate time.Time) []IssueDetails
 			Labels:       issue.Fields.Labels,
 			CustomFields: getCustomFields(issue),
 		}
+		if issue.Fields.Assignee != nil {
+			issueDetails.Assignee = issue.Fields.Assignee.DisplayName
+		}
+		if issue.Fields.Priority != nil {
+			issueDetails.Priority = issue.Fields.Priority.Name
+		}
+		for _, component := range issue.Fields.Components {
+			issueDetails.Components = append(issueDetails.Components, component.Name)
+		}
 
 		previousTransition := &TransitionDetails{
 			Timestamp: issueDetails.CreatedDate,
@@ -139,9 +230,10 @@ func getIssueDetailsList(issues []jira.Issue, endDate time.Time) []IssueDetails
 
 						previousTransition = &t
 
-						if containsStatus(BoardCfg.DoneStatus, t.StatusTo) {
+						statusType := getIssueTypeByStatus(t.StatusTo)
+						if statusType == doneStatusType {
 							issueDetails.ResolvedDate = t.Timestamp
-						} else if issueDetails.WipDate.IsZero() && containsStatus(BoardCfg.WipStatus, t.StatusTo) {
+						} else if issueDetails.WipDate.IsZero() && statusType == wipStatusType {
 							issueDetails.WipDate = t.Timestamp
 						}
 					}
@@ -188,6 +280,18 @@ func getIssueDetailsMapByType(issueDetails []IssueDetails) map[string][]IssueDet
 	return issueDetailsByType
 }
 
+// Statuses missing from the board config are left out of every status type, so they are always reported
+func warnNotMapped(notMapped map[string]int) {
+	if len(notMapped) == 0 {
+		return
+	}
+	var statuses []string
+	for _, status := range getSortedKeys(notMapped) {
+		statuses = append(statuses, fmt.Sprintf("%v (%d transitions)", status, notMapped[status]))
+	}
+	log.Printf("Warning: statuses not mapped in %v: %v", configPath, strings.Join(statuses, ", "))
+}
+
 func getNotMapped(issueDetails []IssueDetails) map[string]int {
 	var notMapped = make(map[string]int)
 	for _, issueDetail := range issueDetails {
@@ -1,193 +1,295 @@
 package main
 
 import (
-	"fmt"
-	"github.com/hako/durafmt"
-	"github.com/zchee/color"
-	"strings"
+	"sort"
 	"time"
 )
 
-func printIssueDetailsByType(issueDetailsMapByType map[string][]IssueDetails) {
-	const separator = " | "
-	for issueType, issueDetails := range issueDetailsMapByType {
-		title("\n>> %s\n", issueType)
-		for _, issueDetails := range issueDetails {
-			toPrint := color.RedString(issueDetails.Key) + separator
-			toPrint += color.WhiteString(issueDetails.Title) + separator
-			toPrint += color.YellowString("Created: %s", formatBrDate(issueDetails.CreatedDate))
-
-			if !issueDetails.WipDate.IsZero() {
-				toPrint += separator
-				toPrint += color.YellowString("To WIP: %s", formatBrDate(issueDetails.WipDate))
-			}
+type IssueSummary struct {
+	Key              string
+	Title            string
+	IssueType        string
+	CreatedDate      time.Time
+	WipDate          *time.Time `json:",omitempty"`
+	ResolvedDate     *time.Time `json:",omitempty"`
+	WipIdle          ReportDuration
+	Wip              ReportDuration
+	LeadTime         *ReportDuration `json:",omitempty"`
+	FlowEfficiency   *float64        `json:",omitempty"`
+	FlagDays         int
+	EpicLink         string   `json:",omitempty"`
+	Labels           []string `json:",omitempty"`
+	CustomFields     []string `json:",omitempty"`
+	Status           string
+	DurationByStatus map[string]ReportDuration
+}
 
-			if !issueDetails.ResolvedDate.IsZero() {
-				toPrint += separator
-				toPrint += color.YellowString("Resolved: %s", formatBrDate(issueDetails.ResolvedDate))
-			}
+type StatusDuration struct {
+	Status   string
+	Percent  float64
+	Duration ReportDuration
+}
 
-			if wipIdle := issueDetails.GetWipAndIdleTotalDuration(); wipIdle > 1 {
-				toPrint += separator
+type WipSummary struct {
+	Tasks       int
+	WorkingDays int
+}
 
-				var days int
-				if wipIdle.Hours() < 24 {
-					days = 1
-				} else {
-					days = getDays(wipIdle)
-				}
-				toPrint += color.WhiteString("WIP/Idle: %d", days)
-			}
+type TypeCount struct {
+	IssueType string
+	Count     int
+	Percent   float64
+}
 
-			if wip := issueDetails.GetWipTotalDuration(); wip > 1 {
-				toPrint += separator
+type Throughput struct {
+	Total  int
+	ByType []TypeCount
+}
 
-				var days int
-				if wip.Hours() < 24 {
-					days = 1
-				} else {
-					days = getDays(wip)
-				}
-				toPrint += color.WhiteString("WIP: %d", days)
-			}
+type TypeDuration struct {
+	IssueType string
+	Average   ReportDuration
+}
 
-			if len(issueDetails.FlagDetails) != 0 {
-				totalFlagDays := 0
-				for _, flag := range issueDetails.FlagDetails {
-					if flagDuration := flag.GetFlagDuration(); flagDuration.Hours() >= 4 {
-						flagDays := getDays(flagDuration)
-						if flagDays < 1 {
-							flagDays = 1
-						}
-						totalFlagDays += flagDays
-					}
-				}
-				if totalFlagDays > 0 {
-					toPrint += separator
-					toPrint += color.WhiteString("Flag: %d", totalFlagDays)
-				}
-			}
+type AverageCycleTime struct {
+	Average ReportDuration
+	ByType  []TypeDuration
+}
 
-			if issueDetails.EpicLink != "" {
-				toPrint += separator
-				toPrint += color.GreenString("Epic: %v", issueDetails.EpicLink)
-			}
-			if len(issueDetails.Labels) > 0 {
-				toPrint += separator
-				toPrint += color.BlueString("Labels: %v", strings.Join(issueDetails.Labels, ", "))
-			}
-			//if issueDetails.Sprint != "" {
-			//	toPrint += separator
-			//	toPrint += color.GreenString("Sprint: %v", issueDetails.Sprint)
-			//}
-			if len(issueDetails.CustomFields) > 0 {
-				for _, customField := range issueDetails.CustomFields {
-					toPrint += separator
-					toPrint += customField.String()
-				}
-			}
-			if issueDetails.TransitionDetails != nil {
-				toPrint += color.YellowString(" (%s)", issueDetails.TransitionDetails.StatusTo)
-			}
-			toPrint += "\n"
-			_, _ = fmt.Fprintf(color.Output, toPrint)
+func getIssueSummaries(issueDetailsMapByType map[string][]IssueDetails) []IssueSummary {
+	var summaries []IssueSummary
+	for _, issueType := range getSortedIssueTypes(issueDetailsMapByType) {
+		for _, issueDetails := range issueDetailsMapByType[issueType] {
+			summaries = append(summaries, getIssueSummary(issueDetails))
 		}
 	}
+	return summaries
 }
 
-func printAverageByStatus(issueDetails []IssueDetails) {
-	totalDurationByStatusMap := make(map[string]time.Duration)
-	var totalDuration time.Duration
-	for _, issueDetails := range issueDetails {
-		for status, duration := range issueDetails.GetDurationByStatus() {
-			totalDurationByStatusMap[status] += duration
-			totalDuration += duration
+func getIssueSummary(issueDetails IssueDetails) IssueSummary {
+	summary := IssueSummary{
+		Key:          issueDetails.Key,
+		Title:        issueDetails.Title,
+		IssueType:    issueDetails.IssueType,
+		CreatedDate:  issueDetails.CreatedDate,
+		WipDate:      timePointer(issueDetails.WipDate),
+		ResolvedDate: timePointer(issueDetails.ResolvedDate),
+		WipIdle:      newReportDuration(issueDetails.GetWipAndIdleTotalDuration()),
+		Wip:          newReportDuration(issueDetails.GetWipTotalDuration()),
+		EpicLink:     issueDetails.EpicLink,
+		Labels:       issueDetails.Labels,
+	}
+
+	if !issueDetails.ResolvedDate.IsZero() {
+		leadTime := newReportDuration(issueDetails.GetLeadTimeDuration())
+		summary.LeadTime = &leadTime
+	}
+
+	active := issueDetails.GetActiveDuration(CLParameters.FlaggedAsWaiting, getReportEnd())
+	if efficiency, ok := getFlowEfficiency(active, issueDetails.GetWipAndIdleTotalDuration()); ok {
+		summary.FlowEfficiency = &efficiency
+	}
+
+	for _, flag := range issueDetails.FlagDetails {
+		if flagDuration := flag.GetFlagDuration(getReportEnd()); flagDuration.Hours() >= 4 {
+			flagDays := getDays(flagDuration)
+			if flagDays < 1 {
+				flagDays = 1
+			}
+			summary.FlagDays += flagDays
 		}
 	}
-	title("\n> Average by Status\n")
-	for status, duration := range totalDurationByStatusMap {
-		statusPercent := float64(duration*100) / float64(totalDuration)
-		fmt.Printf("%v = %.2f%%", status, statusPercent)
-		warn(" [%s]\n", durafmt.Parse(duration))
+
+	summary.DurationByStatus = make(map[string]ReportDuration)
+	for status, duration := range issueDetails.GetDurationByStatus() {
+		summary.DurationByStatus[status] = newReportDuration(duration)
+	}
+
+	for _, customField := range issueDetails.CustomFields {
+		summary.CustomFields = append(summary.CustomFields, customField.String())
 	}
+
+	if issueDetails.TransitionDetails != nil {
+		summary.Status = issueDetails.TransitionDetails.StatusTo
+	}
+	return summary
+}
+
+func getAverageByStatus(issueDetails []IssueDetails) []StatusDuration {
+	return getStatusDurations(issueDetails, func(status string) string {
+		return status
+	})
 }
 
-func printAverageByStatusType(issueDetails []IssueDetails) {
+func getAverageByStatusType(issueDetails []IssueDetails) []StatusDuration {
+	return getStatusDurations(issueDetails, getIssueTypeByStatus)
+}
+
+func getStatusDurations(issueDetails []IssueDetails, statusKey func(string) string) []StatusDuration {
 	var totalDuration time.Duration
-	totalDurationByStatusTypeMap := make(map[string]time.Duration)
+	totalDurationByKeyMap := make(map[string]time.Duration)
 	for _, issueDetails := range issueDetails {
 		for status, duration := range issueDetails.GetDurationByStatus() {
-			totalDurationByStatusTypeMap[getIssueTypeByStatus(status)] += duration
+			totalDurationByKeyMap[statusKey(status)] += duration
 			totalDuration += duration
 		}
 	}
-	title("\n> Average by Status Type\n")
-	for statusType, duration := range totalDurationByStatusTypeMap {
-		statusPercent := float64(duration*100) / float64(totalDuration)
-		fmt.Printf("%v = %.2f%%", statusType, statusPercent)
-		warn(" [%s]\n", durafmt.Parse(duration))
+
+	var statusDurations []StatusDuration
+	for status, duration := range totalDurationByKeyMap {
+		var statusPercent float64
+		if totalDuration > 0 {
+			statusPercent = float64(duration*100) / float64(totalDuration)
+		}
+		statusDurations = append(statusDurations, StatusDuration{
+			Status:   status,
+			Percent:  statusPercent,
+			Duration: newReportDuration(duration),
+		})
 	}
+	sort.Slice(statusDurations, func(i, j int) bool {
+		return statusDurations[i].Status < statusDurations[j].Status
+	})
+	return statusDurations
 }
 
-func printWIP(issueDetails []IssueDetails, weekDays int) {
-	var wipMonthly int
-	var totalWipDuration time.Duration
+func getWIP(issueDetails []IssueDetails, workingDays int) WipSummary {
+	wip := WipSummary{WorkingDays: workingDays}
 	for _, issueDetails := range issueDetails {
-		totalWipDuration += issueDetails.GetWipAndIdleTotalDuration()
 		if issueDetails.GetWipAndIdleTotalDuration().Hours() > 1 {
-			wipMonthly++
+			wip.Tasks++
 		}
 	}
-	title("\n> WIP/Idle\n")
-	fmt.Printf("Monthly: ")
-	warn("%d tasks were in WIP/Idle\n", wipMonthly)
-	//totalWipDays := int(math.Round(totalWipDuration.Hours() / 24))
-	//fmt.Printf("Average: ")
-	//warn("%d tasks\n", wipMonthly/totalWipDays)
+	return wip
 }
 
-func printThroughput(issueDetails []IssueDetails) {
-	var totalThroughput int
+func getThroughput(issueDetails []IssueDetails) Throughput {
+	var throughput Throughput
 	throughputMap := make(map[string]int)
 	for _, issueDetails := range issueDetails {
 		if !issueDetails.ResolvedDate.IsZero() {
 			throughputMap[issueDetails.IssueType]++
-			totalThroughput++
+			throughput.Total++
 		}
 	}
-	title("\n> Throughput\n")
-	fmt.Printf("Total: ")
-	warn("%d tasks delivered\n", totalThroughput)
-	fmt.Printf("By issue type:\n")
-	for issueType, throughput := range throughputMap {
-		fmt.Printf("- %v: %v tasks", issueType, throughput)
-		warn(" (%d%%)\n", (throughput*100)/totalThroughput)
+	for _, issueType := range getSortedKeys(throughputMap) {
+		count := throughputMap[issueType]
+		throughput.ByType = append(throughput.ByType, TypeCount{
+			IssueType: issueType,
+			Count:     count,
+			Percent:   float64(count*100) / float64(throughput.Total),
+		})
 	}
+	return throughput
 }
 
-func printLeadTime(issueDetailsMapByType map[string][]IssueDetails) {
-	var throughputMonthly int
+func getAverageCycleTime(issueDetailsMapByType map[string][]IssueDetails) AverageCycleTime {
+	var cycleTime AverageCycleTime
+	var totalCount int
 	var totalWipDuration time.Duration
-	var leadTimeByTypeMap = make(map[string]float64)
 
-	for issueType, issueDetailsArray := range issueDetailsMapByType {
+	for _, issueType := range getSortedIssueTypes(issueDetailsMapByType) {
+		issueDetailsArray := issueDetailsMapByType[issueType]
+		if len(issueDetailsArray) == 0 {
+			continue
+		}
 		var wipByType time.Duration
 		for _, issueDetails := range issueDetailsArray {
 			wipByType += issueDetails.GetWipAndIdleTotalDuration()
 		}
-		typeThroughput := len(issueDetailsArray)
-		throughputMonthly += typeThroughput
+		totalCount += len(issueDetailsArray)
 		totalWipDuration += wipByType
-		leadTimeByTypeMap[issueType] = float64(wipByType) / float64(typeThroughput)
+		cycleTime.ByType = append(cycleTime.ByType, TypeDuration{
+			IssueType: issueType,
+			Average:   newReportDuration(wipByType / time.Duration(len(issueDetailsArray))),
+		})
+	}
+
+	if totalCount > 0 {
+		cycleTime.Average = newReportDuration(totalWipDuration / time.Duration(totalCount))
+	}
+	return cycleTime
+}
+
+type TypeDistribution struct {
+	IssueType string
+	LeadTime  Distribution
+	CycleTime Distribution
+}
+
+type ServiceLevelBreach struct {
+	Key       string
+	CycleTime ReportDuration
+}
+
+type ServiceLevelResult struct {
+	ServiceLevel
+	Resolved int
+	Within   int
+	Percent  float64
+	Met      bool
+	Breaches []ServiceLevelBreach
+}
+
+// Lead time goes from creation to resolution, cycle time is the time spent in WIP and Idle statuses.
+// Only resolved issues are considered, the last entry holds all issue types together.
+func getDistributions(issueDetailsMapByType map[string][]IssueDetails) []TypeDistribution {
+	var distributions []TypeDistribution
+	var allLeadTimes, allCycleTimes []time.Duration
+	for _, issueType := range getSortedIssueTypes(issueDetailsMapByType) {
+		var leadTimes, cycleTimes []time.Duration
+		for _, issueDetails := range issueDetailsMapByType[issueType] {
+			if !issueDetails.ResolvedDate.IsZero() {
+				leadTimes = append(leadTimes, issueDetails.GetLeadTimeDuration())
+				cycleTimes = append(cycleTimes, issueDetails.GetWipAndIdleTotalDuration())
+			}
+		}
+		if len(leadTimes) == 0 {
+			continue
+		}
+		allLeadTimes = append(allLeadTimes, leadTimes...)
+		allCycleTimes = append(allCycleTimes, cycleTimes...)
+		distributions = append(distributions, TypeDistribution{
+			IssueType: issueType,
+			LeadTime:  getDistribution(leadTimes),
+			CycleTime: getDistribution(cycleTimes),
+		})
+	}
+	if len(allLeadTimes) > 0 {
+		distributions = append(distributions, TypeDistribution{
+			IssueType: allIssueTypes,
+			LeadTime:  getDistribution(allLeadTimes),
+			CycleTime: getDistribution(allCycleTimes),
+		})
 	}
+	return distributions
+}
 
-	title("\n> Lead time\n")
-	fmt.Printf("Average: ")
-	wipDays := getDays(totalWipDuration)
-	warn("%d days\n", wipDays/throughputMonthly)
-	fmt.Printf("By issue type:\n")
-	for issueType, leadTime := range leadTimeByTypeMap {
-		fmt.Printf("- %v: ", issueType)
-		warn("%d days\n", getDays(time.Duration(leadTime)))
+// Checks the cycle time of resolved issues against each service level expectation in the board configuration
+func getServiceLevels(issueDetails []IssueDetails) []ServiceLevelResult {
+	var results []ServiceLevelResult
+	for _, serviceLevel := range BoardCfg.ServiceLevels {
+		result := ServiceLevelResult{ServiceLevel: serviceLevel}
+		for _, issueDetails := range issueDetails {
+			if issueDetails.ResolvedDate.IsZero() || !serviceLevel.appliesTo(issueDetails.IssueType) {
+				continue
+			}
+			result.Resolved++
+			cycleTime := issueDetails.GetWipAndIdleTotalDuration()
+			if getWorkingDaysFloat(cycleTime) <= serviceLevel.Days {
+				result.Within++
+			} else {
+				result.Breaches = append(result.Breaches, ServiceLevelBreach{
+					Key:       issueDetails.Key,
+					CycleTime: newReportDuration(cycleTime),
+				})
+			}
+		}
+		if result.Resolved > 0 {
+			result.Percent = float64(result.Within*100) / float64(result.Resolved)
+			result.Met = result.Percent >= serviceLevel.Percentile
+		}
+		results = append(results, result)
 	}
+	return results
 }
@@ -26,6 +26,13 @@ func formatBrDate(date time.Time) string {
 	return date.Format(brDateFormat)
 }
 
+func formatOptionalBrDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return formatBrDate(*date)
+}
+
 func formatBrDateWithTime(date time.Time) string {
 	const brDateFormat = "02/01/2006 15:04:05"
 	return date.Format(brDateFormat)
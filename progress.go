@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"golang.org/x/term"
+	"os"
+	"strings"
+	"sync"
+)
+
+const progressBarWidth = 40
+
+// Progress bar drawn on stderr, only when stderr is a terminal so redirected output stays clean
+type progressBar struct {
+	label   string
+	total   int
+	done    int
+	enabled bool
+	mutex   sync.Mutex
+}
+
+func newProgressBar(label string, total int) *progressBar {
+	p := &progressBar{
+		label:   label,
+		total:   total,
+		enabled: term.IsTerminal(int(os.Stderr.Fd())) && !CLParameters.Debug,
+	}
+	p.draw()
+	return p
+}
+
+func (p *progressBar) add(n int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.done += n
+	p.draw()
+}
+
+func (p *progressBar) finish() {
+	if p.enabled {
+		_, _ = fmt.Fprintln(os.Stderr)
+	}
+}
+
+func (p *progressBar) draw() {
+	if !p.enabled || p.total <= 0 {
+		return
+	}
+	filled := p.done * progressBarWidth / p.total
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	_, _ = fmt.Fprintf(os.Stderr, "\r%s [%s] %d/%d", p.label, bar, p.done, p.total)
+}
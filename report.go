@@ -0,0 +1,135 @@
+package main
+
+import (
+	"io"
+	"log"
+	"math"
+	"os"
+	"time"
+)
+
+// Report holds every metric section computed for a run, independently of how it is rendered.
+type Report struct {
+	Project             string
+	Board               string `json:",omitempty"`
+	StartDate           string
+	EndDate             string
+	Jql                 string `json:",omitempty"`
+	DurationModel       string
+	Issues              []IssueSummary
+	NotMapped           map[string]int `json:",omitempty"`
+	AverageByStatus     []StatusDuration
+	AverageByStatusType []StatusDuration
+	Wip                 WipSummary
+	Throughput          Throughput
+	ThroughputRunChart  ThroughputRunChart
+	AverageCycleTime    AverageCycleTime
+	Distributions       []TypeDistribution
+	FlowEfficiency      FlowEfficiency
+	BlockedTime         BlockedTime
+	ServiceLevels       []ServiceLevelResult `json:",omitempty"`
+	GroupBy             string               `json:",omitempty"`
+	Groups              []GroupMetrics       `json:",omitempty"`
+}
+
+// ReportDuration exposes a duration in hours and in working days (non working days are already discounted).
+type ReportDuration struct {
+	Hours float64
+	Days  float64
+	Value time.Duration `json:"-"`
+}
+
+func newReportDuration(duration time.Duration) ReportDuration {
+	return ReportDuration{
+		Hours: roundFloat(duration.Hours()),
+		Days:  roundFloat(getWorkingDaysFloat(duration)),
+		Value: duration,
+	}
+}
+
+func buildReport(issueDetails []IssueDetails, startDate, endDate time.Time, interval string) Report {
+	byType := getIssueDetailsMapByType(issueDetails)
+	workingDays := countWorkingDays(startDate, endDate)
+	report := Report{
+		Project:             BoardCfg.Project,
+		Board:               BoardCfg.Board,
+		StartDate:           CLParameters.StartDate,
+		EndDate:             CLParameters.EndDate,
+		Jql:                 CLParameters.Jql,
+		DurationModel:       Calendar.durationModel(),
+		Issues:              getIssueSummaries(byType),
+		NotMapped:           getNotMapped(issueDetails),
+		AverageByStatus:     getAverageByStatus(issueDetails),
+		AverageByStatusType: getAverageByStatusType(issueDetails),
+		Wip:                 getWIP(issueDetails, workingDays),
+		Throughput:          getThroughput(issueDetails),
+		ThroughputRunChart:  getThroughputRunChart(issueDetails, startDate, endDate, interval),
+		AverageCycleTime:    getAverageCycleTime(byType),
+		Distributions:       getDistributions(byType),
+		FlowEfficiency:      getFlowEfficiencies(byType, getReportEnd()),
+		BlockedTime:         getBlockedTime(byType, startDate, getReportEnd()),
+		ServiceLevels:       getServiceLevels(issueDetails),
+	}
+	if CLParameters.GroupBy != "" {
+		report.GroupBy = CLParameters.GroupBy
+		report.Groups = getGroups(issueDetails, CLParameters.GroupBy, workingDays)
+	}
+	return report
+}
+
+// issueDetails are only used by the html report, which charts their cumulative flow
+func writeReport(report Report, cumulativeFlow CumulativeFlow) {
+	switch CLParameters.Format {
+	case "text":
+		printReport(report)
+	case "json":
+		withOutput(func(output io.Writer) {
+			printJSONReport(output, report)
+		})
+	case "csv":
+		withOutput(func(output io.Writer) {
+			printCSVReport(output, report)
+		})
+	case "html":
+		withOutput(func(output io.Writer) {
+			printHTMLReport(output, report, cumulativeFlow)
+		})
+	default:
+		log.Fatalf("Unknown output format: %v", CLParameters.Format)
+	}
+}
+
+// Calls write with the file given by --output, or with stdout when it is not set
+func withOutput(write func(io.Writer)) {
+	if CLParameters.Output == "" {
+		write(os.Stdout)
+		return
+	}
+
+	file, err := os.Create(CLParameters.Output)
+	if err != nil {
+		log.Fatalf("Failed to create output file %v: %v", CLParameters.Output, err)
+	}
+	defer file.Close()
+	write(file)
+}
+
+// End of the report window, the day after the end date, or now when the issues come from a JQL
+func getReportEnd() time.Time {
+	endDate := parseDate(CLParameters.EndDate)
+	if endDate.IsZero() {
+		return time.Now()
+	}
+	return endDate.AddDate(0, 0, 1)
+}
+
+func timePointer(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func roundFloat(value float64) float64 {
+	return math.Round(value*100) / 100
+}
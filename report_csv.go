@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{"Key", "Title", "Type", "Created", "WIP Date", "Resolved Date", "WIP Days",
+	"WIP/Idle Days", "Flagged Days", "Flow Efficiency", "Epic", "Labels", "Status"}
+
+// Writes one row per issue, followed by the days spent in each status configured in the board
+func printCSVReport(output io.Writer, report Report) {
+	configuredStatuses := getConfiguredStatuses()
+
+	records := [][]string{append(append([]string{}, csvHeader...), configuredStatuses...)}
+	for _, issue := range report.Issues {
+		row := []string{
+			issue.Key,
+			issue.Title,
+			issue.IssueType,
+			formatBrDate(issue.CreatedDate),
+			formatOptionalBrDate(issue.WipDate),
+			formatOptionalBrDate(issue.ResolvedDate),
+			formatCSVFloat(issue.Wip.Days),
+			formatCSVFloat(issue.WipIdle.Days),
+			strconv.Itoa(issue.FlagDays),
+			formatOptionalCSVFloat(issue.FlowEfficiency),
+			issue.EpicLink,
+			strings.Join(issue.Labels, ", "),
+			issue.Status,
+		}
+		for _, configuredStatus := range configuredStatuses {
+			var days float64
+			for status, duration := range issue.DurationByStatus {
+				if getConfiguredStatusName(status) == configuredStatus {
+					days += duration.Days
+				}
+			}
+			row = append(row, formatCSVFloat(days))
+		}
+		records = append(records, row)
+	}
+	writeCSV(output, records, "report")
+}
+
+// Writes the records and flushes them, the name only shows in the error message
+func writeCSV(output io.Writer, records [][]string, name string) {
+	if err := csv.NewWriter(output).WriteAll(records); err != nil {
+		log.Fatalf("Failed to write %s CSV: %v", name, err)
+	}
+}
+
+func formatCSVFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func formatOptionalCSVFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatCSVFloat(*value)
+}
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Colors of the issue types in the charts, repeated when there are more types
+var htmlPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7",
+	"#9c755f", "#bab0ac"}
+
+var statusTypeColors = map[string]string{
+	openStatusType:      "#bab0ac",
+	wipStatusType:       "#4e79a7",
+	idleStatusType:      "#edc948",
+	doneStatusType:      "#59a14f",
+	notMappedStatusType: "#e15759",
+}
+
+// Status types from the bottom to the top of the cumulative flow diagram
+var cumulativeFlowLayers = []string{doneStatusType, idleStatusType, wipStatusType, openStatusType, notMappedStatusType}
+
+type htmlStatusMapping struct {
+	StatusType string
+	Statuses   string
+}
+
+type htmlReport struct {
+	Report              Report
+	ConfigFile          string
+	Generated           time.Time
+	Filter              string
+	StatusMapping       []htmlStatusMapping
+	ScatterChart        template.HTML
+	ThroughputChart     template.HTML
+	CumulativeFlowChart template.HTML
+	LeadTimeHistogram   template.HTML
+}
+
+// Writes the report as a single HTML page with inline styles, scripts and SVG charts, so it can be read offline
+func printHTMLReport(output io.Writer, report Report, cumulativeFlow CumulativeFlow) {
+	page := htmlReport{
+		Report:     report,
+		ConfigFile: configPath,
+		Generated:  time.Now(),
+		Filter:     CLParameters.Filter,
+		StatusMapping: []htmlStatusMapping{
+			{openStatusType, strings.Join(BoardCfg.OpenStatus, ", ")},
+			{wipStatusType, strings.Join(BoardCfg.WipStatus, ", ")},
+			{idleStatusType, strings.Join(BoardCfg.IdleStatus, ", ")},
+			{doneStatusType, strings.Join(BoardCfg.DoneStatus, ", ")},
+		},
+		ScatterChart:        getCycleTimeScatterChart(report),
+		ThroughputChart:     getThroughputChart(report.ThroughputRunChart),
+		CumulativeFlowChart: getCumulativeFlowChart(cumulativeFlow),
+		LeadTimeHistogram:   getLeadTimeHistogram(report.Issues),
+	}
+
+	err := htmlReportTemplate.Execute(output, page)
+	if err != nil {
+		log.Fatalf("Failed to write HTML report: %v", err)
+	}
+}
+
+// svgPlot draws a chart in a fixed size view box, values being scaled to the area inside the margins
+type svgPlot struct {
+	width, height            float64
+	left, right, top, bottom float64
+	body                     strings.Builder
+}
+
+func newSVGPlot() *svgPlot {
+	return &svgPlot{width: 760, height: 280, left: 50, right: 20, top: 15, bottom: 45}
+}
+
+func (p *svgPlot) plotWidth() float64 {
+	return p.width - p.left - p.right
+}
+
+func (p *svgPlot) plotHeight() float64 {
+	return p.height - p.top - p.bottom
+}
+
+func (p *svgPlot) x(ratio float64) float64 {
+	return p.left + p.plotWidth()*ratio
+}
+
+func (p *svgPlot) y(value, max float64) float64 {
+	return p.top + p.plotHeight()*(1-value/max)
+}
+
+func (p *svgPlot) add(format string, args ...interface{}) {
+	p.body.WriteString(fmt.Sprintf(format, args...))
+	p.body.WriteString("\n")
+}
+
+// Draws the y axis with its grid lines and returns the rounded up maximum of the scale
+func (p *svgPlot) yAxis(max float64, label string) float64 {
+	step := getNiceStep(max / 5)
+	max = math.Max(step, math.Ceil(max/step)*step)
+	for value := 0.0; value <= max+step/2; value += step {
+		y := p.y(value, max)
+		p.add(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="grid"/>`, p.left, y, p.width-p.right, y)
+		p.add(`<text x="%.1f" y="%.1f" class="tick" text-anchor="end">%v</text>`, p.left-6, y+4, value)
+	}
+	p.add(`<text x="12" y="%.1f" class="label" transform="rotate(-90 12 %.1f)" text-anchor="middle">%s</text>`,
+		p.top+p.plotHeight()/2, p.top+p.plotHeight()/2, template.HTMLEscapeString(label))
+	return max
+}
+
+func (p *svgPlot) xLabel(x float64, text string) {
+	p.add(`<text x="%.1f" y="%.1f" class="tick" text-anchor="middle">%s</text>`, x, p.height-p.bottom+16,
+		template.HTMLEscapeString(text))
+}
+
+func (p *svgPlot) html(legend []string, colors []string) template.HTML {
+	var svg strings.Builder
+	svg.WriteString(fmt.Sprintf(`<svg viewBox="0 0 %.0f %.0f" class="chart">`, p.width, p.height))
+	svg.WriteString("\n")
+	svg.WriteString(p.body.String())
+	svg.WriteString(fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="axis"/>`, p.left,
+		p.height-p.bottom, p.width-p.right, p.height-p.bottom))
+	svg.WriteString("</svg>\n")
+	if len(legend) > 0 {
+		svg.WriteString(`<div class="legend">`)
+		for i, name := range legend {
+			svg.WriteString(fmt.Sprintf(`<span><i style="background:%s"></i>%s</span>`, colors[i],
+				template.HTMLEscapeString(name)))
+		}
+		svg.WriteString("</div>\n")
+	}
+	return template.HTML(svg.String())
+}
+
+// Rounds the step of an axis to 1, 2 or 5 times a power of ten
+func getNiceStep(step float64) float64 {
+	if step <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(step)))
+	for _, multiple := range []float64{1, 2, 5, 10} {
+		if step <= multiple*magnitude {
+			return math.Max(multiple*magnitude, 1)
+		}
+	}
+	return 10 * magnitude
+}
+
+func getIssueTypeColors(issueTypes []string) []string {
+	var colors []string
+	for i := range issueTypes {
+		colors = append(colors, htmlPalette[i%len(htmlPalette)])
+	}
+	return colors
+}
+
+func getIssueTypes(issues []IssueSummary) []string {
+	issueTypesMap := make(map[string]int)
+	for _, issue := range issues {
+		issueTypesMap[issue.IssueType]++
+	}
+	return getSortedKeys(issueTypesMap)
+}
+
+// Cycle time of the resolved issues by resolution date, with the 50th, 85th and 95th percentiles of all types
+func getCycleTimeScatterChart(report Report) template.HTML {
+	var resolved []IssueSummary
+	maxDays := 0.0
+	for _, issue := range report.Issues {
+		if issue.ResolvedDate != nil {
+			resolved = append(resolved, issue)
+			maxDays = math.Max(maxDays, issue.WipIdle.Days)
+		}
+	}
+	if len(resolved) == 0 {
+		return ""
+	}
+
+	start, end := parseDate(report.StartDate), parseDate(report.EndDate).AddDate(0, 0, 1)
+	if start.IsZero() || parseDate(report.EndDate).IsZero() {
+		start, end = *resolved[0].ResolvedDate, *resolved[0].ResolvedDate
+		for _, issue := range resolved {
+			if issue.ResolvedDate.Before(start) {
+				start = *issue.ResolvedDate
+			}
+			if issue.ResolvedDate.After(end) {
+				end = *issue.ResolvedDate
+			}
+		}
+		end = end.Add(time.Hour)
+	}
+	span := end.Sub(start).Hours()
+
+	var percentiles []PercentileDuration
+	if len(report.Distributions) > 0 {
+		percentiles = report.Distributions[len(report.Distributions)-1].CycleTime.Percentiles
+	}
+	for _, percentile := range percentiles {
+		maxDays = math.Max(maxDays, percentile.Duration.Days)
+	}
+
+	plot := newSVGPlot()
+	max := plot.yAxis(maxDays, "Cycle time (days)")
+	for _, percentile := range percentiles {
+		if percentile.Percentile == 70 {
+			continue
+		}
+		y := plot.y(percentile.Duration.Days, max)
+		plot.add(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="percentile"/>`, plot.left, y, plot.width-plot.right, y)
+		plot.add(`<text x="%.1f" y="%.1f" class="tick" text-anchor="end">%dth</text>`, plot.width-plot.right, y-3,
+			percentile.Percentile)
+	}
+
+	issueTypes := getIssueTypes(resolved)
+	colors := getIssueTypeColors(issueTypes)
+	for _, issue := range resolved {
+		x := plot.x(issue.ResolvedDate.Sub(start).Hours() / span)
+		color := colors[sort.SearchStrings(issueTypes, issue.IssueType)]
+		plot.add(`<circle cx="%.1f" cy="%.1f" r="4" fill="%s"><title>%s: %.1f days</title></circle>`, x,
+			plot.y(issue.WipIdle.Days, max), color, template.HTMLEscapeString(issue.Key), issue.WipIdle.Days)
+	}
+	for i := 0; i <= 5; i++ {
+		date := start.Add(time.Duration(float64(end.Sub(start)) * float64(i) / 5))
+		plot.xLabel(plot.x(float64(i)/5), formatBrDate(date))
+	}
+	return plot.html(issueTypes, colors)
+}
+
+// Throughput of each interval of the run chart, stacked by issue type, skipped without a date range
+func getThroughputChart(runChart ThroughputRunChart) template.HTML {
+	if len(runChart.Intervals) == 0 || runChart.Intervals[0].Start.IsZero() {
+		return ""
+	}
+	issueTypesMap := make(map[string]int)
+	maxTotal := 0.0
+	for _, interval := range runChart.Intervals {
+		for issueType, count := range interval.ByType {
+			issueTypesMap[issueType] += count
+		}
+		maxTotal = math.Max(maxTotal, float64(interval.Total))
+	}
+	issueTypes := getSortedKeys(issueTypesMap)
+	colors := getIssueTypeColors(issueTypes)
+
+	plot := newSVGPlot()
+	max := plot.yAxis(maxTotal, fmt.Sprintf("Tasks per %s", runChart.Interval))
+	slot := plot.plotWidth() / float64(len(runChart.Intervals))
+	labelEvery := int(math.Ceil(float64(len(runChart.Intervals)) / 10))
+	var averagePoints []string
+	for i, interval := range runChart.Intervals {
+		x := plot.left + slot*float64(i)
+		var stacked float64
+		for j, issueType := range issueTypes {
+			count := float64(interval.ByType[issueType])
+			if count == 0 {
+				continue
+			}
+			top := plot.y(stacked+count, max)
+			plot.add(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s %s: %v</title></rect>`,
+				x+slot*0.1, top, slot*0.8, plot.y(stacked, max)-top, colors[j], formatBrDate(interval.Start),
+				template.HTMLEscapeString(issueType), count)
+			stacked += count
+		}
+		averagePoints = append(averagePoints, fmt.Sprintf("%.1f,%.1f", x+slot/2, plot.y(interval.RollingAverage, max)))
+		if i%labelEvery == 0 {
+			plot.xLabel(x+slot/2, formatBrDate(interval.Start))
+		}
+	}
+	plot.add(`<polyline points="%s" class="average"/>`, strings.Join(averagePoints, " "))
+	return plot.html(append(issueTypes, "Rolling average"), append(colors, "#333"))
+}
+
+// Issues in each status type at the end of every working day, Done at the bottom
+func getCumulativeFlowChart(cumulativeFlow CumulativeFlow) template.HTML {
+	days := cumulativeFlow.Days
+	if len(days) == 0 {
+		return ""
+	}
+	maxTotal := 0.0
+	for _, day := range days {
+		var total int
+		for _, count := range day.ByStatusType {
+			total += count
+		}
+		maxTotal = math.Max(maxTotal, float64(total))
+	}
+
+	plot := newSVGPlot()
+	max := plot.yAxis(maxTotal, "Issues")
+	dayX := func(i int) float64 {
+		if len(days) == 1 {
+			return plot.x(0.5)
+		}
+		return plot.x(float64(i) / float64(len(days)-1))
+	}
+
+	var layers, colors []string
+	lower := make([]float64, len(days))
+	for _, statusType := range cumulativeFlowLayers {
+		upper := make([]float64, len(days))
+		var found bool
+		for i, day := range days {
+			upper[i] = lower[i] + float64(day.ByStatusType[statusType])
+			found = found || day.ByStatusType[statusType] > 0
+		}
+		if !found {
+			continue
+		}
+		var points []string
+		for i := range days {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", dayX(i), plot.y(upper[i], max)))
+		}
+		for i := len(days) - 1; i >= 0; i-- {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", dayX(i), plot.y(lower[i], max)))
+		}
+		plot.add(`<polygon points="%s" fill="%s"><title>%s</title></polygon>`, strings.Join(points, " "),
+			statusTypeColors[statusType], statusType)
+		layers = append(layers, statusType)
+		colors = append(colors, statusTypeColors[statusType])
+		lower = upper
+	}
+
+	labelEvery := int(math.Ceil(float64(len(days)) / 8))
+	for i, day := range days {
+		if i%labelEvery == 0 {
+			plot.xLabel(dayX(i), formatBrDate(day.Date))
+		}
+	}
+	return plot.html(layers, colors)
+}
+
+// Number of resolved issues by lead time, in buckets of whole days
+func getLeadTimeHistogram(issues []IssueSummary) template.HTML {
+	var leadTimes []float64
+	maxDays := 0.0
+	for _, issue := range issues {
+		if issue.LeadTime != nil {
+			leadTimes = append(leadTimes, issue.LeadTime.Days)
+			maxDays = math.Max(maxDays, issue.LeadTime.Days)
+		}
+	}
+	if len(leadTimes) == 0 {
+		return ""
+	}
+
+	bucketDays := math.Max(1, math.Ceil((maxDays+1)/20))
+	counts := make([]int, int(maxDays/bucketDays)+1)
+	maxCount := 0.0
+	for _, leadTime := range leadTimes {
+		bucket := int(leadTime / bucketDays)
+		counts[bucket]++
+		maxCount = math.Max(maxCount, float64(counts[bucket]))
+	}
+
+	plot := newSVGPlot()
+	max := plot.yAxis(maxCount, "Issues")
+	slot := plot.plotWidth() / float64(len(counts))
+	for i, count := range counts {
+		from := float64(i) * bucketDays
+		x := plot.left + slot*float64(i)
+		if count > 0 {
+			top := plot.y(float64(count), max)
+			plot.add(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%v-%v days: %d</title></rect>`,
+				x+1, top, slot-2, plot.y(0, max)-top, htmlPalette[0], from, from+bucketDays, count)
+		}
+		plot.xLabel(x+slot/2, fmt.Sprint(from))
+	}
+	plot.add(`<text x="%.1f" y="%.1f" class="label" text-anchor="middle">Lead time (days)</text>`,
+		plot.x(0.5), plot.height-6)
+	return plot.html(nil, nil)
+}
+
+func formatHTMLDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return formatBrDate(*date)
+}
+
+func formatHTMLSortDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":     formatHTMLDate,
+	"sortDate": formatHTMLSortDate,
+	"dateOf": func(date time.Time) *time.Time {
+		return &date
+	},
+	"join": strings.Join,
+	"days": func(duration ReportDuration) string {
+		return fmt.Sprintf("%.1f", duration.Days)
+	},
+	"percent": func(value float64) string {
+		return fmt.Sprintf("%.1f%%", value)
+	},
+}).Parse(htmlReportPage))
+
+const htmlReportPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Kanban metrics {{.Report.Project}} {{.Report.StartDate}} - {{.Report.EndDate}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #222; padding: 0 1em; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.25em; margin-top: 2em; border-bottom: 1px solid #ddd; padding-bottom: .3em; }
+table { border-collapse: collapse; margin: .5em 0 1em; font-size: .9em; }
+th, td { border: 1px solid #ddd; padding: .3em .6em; text-align: left; }
+th { background: #f4f4f4; }
+table.sortable th { cursor: pointer; user-select: none; }
+table.sortable th:after { content: " \2195"; color: #aaa; }
+td.number { text-align: right; }
+.chart { width: 100%; max-width: 760px; display: block; }
+.chart .grid { stroke: #eee; } .chart .axis { stroke: #888; }
+.chart .tick { font-size: 10px; fill: #555; } .chart .label { font-size: 11px; fill: #333; }
+.chart .percentile { stroke: #e15759; stroke-dasharray: 4 3; }
+.chart .average { fill: none; stroke: #333; stroke-width: 2; }
+.legend span { margin-right: 1em; font-size: .85em; } .legend i { display: inline-block; width: .8em; height: .8em; margin-right: .3em; }
+.breached { color: #c0392b; } .met { color: #27ae60; }
+</style>
+</head>
+<body>
+<h1>Kanban metrics of project {{.Report.Project}}{{if .Report.Board}} ({{.Report.Board}}){{end}}</h1>
+
+<h2>Configuration</h2>
+<table>
+<tr><th>Period</th><td>{{.Report.StartDate}} - {{.Report.EndDate}}</td></tr>
+{{if .Report.Jql}}<tr><th>JQL</th><td>{{.Report.Jql}}</td></tr>{{end}}
+<tr><th>Config file</th><td>{{.ConfigFile}}</td></tr>
+<tr><th>Durations</th><td>{{.Report.DurationModel}}</td></tr>
+{{range .StatusMapping}}<tr><th>{{.StatusType}} statuses</th><td>{{.Statuses}}</td></tr>
+{{end}}{{if .Report.GroupBy}}<tr><th>Group by</th><td>{{.Report.GroupBy}}</td></tr>{{end}}
+{{if .Filter}}<tr><th>Filter</th><td>{{.Filter}}</td></tr>{{end}}
+<tr><th>Generated</th><td>{{.Generated.Format "02/01/2006 15:04"}}</td></tr>
+</table>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Throughput</th><td>{{.Report.Throughput.Total}} tasks delivered</td></tr>
+<tr><th>WIP/Idle</th><td>{{.Report.Wip.Tasks}} tasks</td></tr>
+<tr><th>Average cycle time</th><td>{{days .Report.AverageCycleTime.Average}} days</td></tr>
+{{with .Report.ThroughputRunChart}}{{if .Intervals}}<tr><th>Throughput trend</th><td>{{.Trend}} ({{printf "%+.2f" .Slope}} tasks per {{.Interval}})</td></tr>{{end}}{{end}}
+</table>
+{{if .Report.ServiceLevels}}
+<table>
+<tr><th>Service level expectation</th><th>Within</th><th>Result</th></tr>
+{{range .Report.ServiceLevels}}<tr><td>{{.ServiceLevel}}</td><td class="number">{{percent .Percent}} ({{.Within}} of {{.Resolved}})</td>
+<td>{{if eq .Resolved 0}}no resolved issues{{else if .Met}}<span class="met">met</span>{{else}}<span class="breached">breached</span>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .ScatterChart}}<h2>Cycle time scatterplot</h2>
+{{.ScatterChart}}{{end}}
+
+{{if .ThroughputChart}}<h2>Throughput by {{.Report.ThroughputRunChart.Interval}}</h2>
+{{.ThroughputChart}}{{end}}
+
+{{if .CumulativeFlowChart}}<h2>Cumulative flow</h2>
+{{.CumulativeFlowChart}}{{end}}
+
+{{if .LeadTimeHistogram}}<h2>Lead time histogram</h2>
+{{.LeadTimeHistogram}}{{end}}
+
+{{if .Report.Distributions}}<h2>Lead and cycle time distribution (days)</h2>
+<table class="sortable">
+<thead><tr><th>Type</th><th>Count</th><th>Lead time 50%</th><th>Lead time 85%</th><th>Lead time 95%</th><th>Cycle time 50%</th><th>Cycle time 85%</th><th>Cycle time 95%</th></tr></thead>
+<tbody>
+{{range .Report.Distributions}}<tr><td>{{.IssueType}}</td><td class="number">{{.LeadTime.Count}}</td>
+{{range .LeadTime.Percentiles}}{{if ne .Percentile 70}}<td class="number">{{days .Duration}}</td>{{end}}{{end}}
+{{range .CycleTime.Percentiles}}{{if ne .Percentile 70}}<td class="number">{{days .Duration}}</td>{{end}}{{end}}</tr>
+{{end}}</tbody>
+</table>{{end}}
+
+<h2>Status breakdown</h2>
+<table class="sortable">
+<thead><tr><th>Status</th><th>Share</th><th>Days</th></tr></thead>
+<tbody>
+{{range .Report.AverageByStatus}}<tr><td>{{.Status}}</td><td class="number" data-value="{{.Percent}}">{{percent .Percent}}</td><td class="number">{{days .Duration}}</td></tr>
+{{end}}</tbody>
+</table>
+<table class="sortable">
+<thead><tr><th>Status type</th><th>Share</th><th>Days</th></tr></thead>
+<tbody>
+{{range .Report.AverageByStatusType}}<tr><td>{{.Status}}</td><td class="number" data-value="{{.Percent}}">{{percent .Percent}}</td><td class="number">{{days .Duration}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Issues</h2>
+<table class="sortable">
+<thead><tr><th>Key</th><th>Title</th><th>Type</th><th>Status</th><th>Created</th><th>WIP</th><th>Resolved</th>
+<th>Lead time</th><th>WIP/Idle days</th><th>WIP days</th><th>Efficiency</th><th>Flag days</th><th>Epic</th><th>Labels</th></tr></thead>
+<tbody>
+{{range .Report.Issues}}<tr><td>{{.Key}}</td><td>{{.Title}}</td><td>{{.IssueType}}</td><td>{{.Status}}</td>
+<td data-value="{{sortDate (dateOf .CreatedDate)}}">{{date (dateOf .CreatedDate)}}</td>
+<td data-value="{{sortDate .WipDate}}">{{date .WipDate}}</td>
+<td data-value="{{sortDate .ResolvedDate}}">{{date .ResolvedDate}}</td>
+<td class="number">{{with .LeadTime}}{{days .}}{{end}}</td>
+<td class="number">{{days .WipIdle}}</td><td class="number">{{days .Wip}}</td>
+<td class="number">{{with .FlowEfficiency}}{{percent .}}{{end}}</td>
+<td class="number">{{.FlagDays}}</td><td>{{.EpicLink}}</td><td>{{join .Labels ", "}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<script>
+var number = /^-?[0-9]+(\.[0-9]+)?%?$/;
+document.querySelectorAll("table.sortable th").forEach(function (th) {
+	th.addEventListener("click", function () {
+		var table = th.closest("table");
+		var tbody = table.tBodies[0];
+		var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+		var ascending = th.getAttribute("data-order") !== "asc";
+		th.setAttribute("data-order", ascending ? "asc" : "desc");
+		var value = function (row) {
+			var cell = row.children[index];
+			return cell.hasAttribute("data-value") ? cell.getAttribute("data-value") : cell.textContent.trim();
+		};
+		var rows = Array.prototype.slice.call(tbody.rows);
+		rows.sort(function (a, b) {
+			var x = value(a), y = value(b);
+			var compared = number.test(x) && number.test(y) ? parseFloat(x) - parseFloat(y) : x.localeCompare(y);
+			return ascending ? compared : -compared;
+		});
+		rows.forEach(function (row) {
+			tbody.appendChild(row);
+		});
+	});
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetNiceStep(t *testing.T) {
+	tests := []struct {
+		step float64
+		want float64
+	}{
+		{-1, 1},
+		{0, 1},
+		{0.3, 1},
+		{1, 1},
+		{1.5, 2},
+		{3, 5},
+		{7, 10},
+		{12, 20},
+		{45, 50},
+		{180, 200},
+	}
+	for _, test := range tests {
+		if got := getNiceStep(test.step); got != test.want {
+			t.Errorf("getNiceStep(%v) = %v, want %v", test.step, got, test.want)
+		}
+	}
+}
+
+func newLeadTimeIssues(days ...float64) []IssueSummary {
+	issues := []IssueSummary{{Key: "PRJ-0"}}
+	for _, day := range days {
+		leadTime := ReportDuration{Days: day, Value: time.Duration(day * float64(24*time.Hour))}
+		issues = append(issues, IssueSummary{Key: "PRJ-1", LeadTime: &leadTime})
+	}
+	return issues
+}
+
+func checkXML(document string) error {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+var histogramBarTitle = regexp.MustCompile(`<title>([^<]+)</title>`)
+
+func TestGetLeadTimeHistogram(t *testing.T) {
+	tests := []struct {
+		name string
+		days []float64
+		bars []string
+	}{
+		{"no resolved issues", nil, nil},
+		{"daily buckets", []float64{0.5, 1.2, 1.8, 3}, []string{"0-1 days: 1", "1-2 days: 2", "3-4 days: 1"}},
+		{"wider buckets", []float64{2, 44.5, 45}, []string{"0-3 days: 1", "42-45 days: 1", "45-48 days: 1"}},
+	}
+	for _, test := range tests {
+		histogram := string(getLeadTimeHistogram(newLeadTimeIssues(test.days...)))
+		if test.bars == nil {
+			if histogram != "" {
+				t.Errorf("%v: got a histogram, want none", test.name)
+			}
+			continue
+		}
+		if err := checkXML(histogram); err != nil {
+			t.Errorf("%v: invalid SVG: %v", test.name, err)
+		}
+		var bars []string
+		for _, match := range histogramBarTitle.FindAllStringSubmatch(histogram, -1) {
+			bars = append(bars, match[1])
+		}
+		if !reflect.DeepEqual(bars, test.bars) {
+			t.Errorf("%v: bars = %v, want %v", test.name, bars, test.bars)
+		}
+		if !strings.Contains(histogram, "Lead time (days)") {
+			t.Errorf("%v: missing axis label", test.name)
+		}
+	}
+}
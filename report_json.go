@@ -0,0 +1,20 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+)
+
+func printJSONReport(output io.Writer, report Report) {
+	writeJSON(output, report, "report")
+}
+
+// Writes the value as indented JSON, the name only shows in the error message
+func writeJSON(output io.Writer, value interface{}, name string) {
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		log.Fatalf("Failed to encode %s: %v", name, err)
+	}
+}
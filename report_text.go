@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hako/durafmt"
+	"github.com/zchee/color"
+	"strings"
+	"time"
+)
+
+func printReport(report Report) {
+	title("Extracting Kanban metrics from project %s // ", report.Project)
+	title("From %s to %s\n", report.StartDate, report.EndDate)
+	info("Durations in %s\n", report.DurationModel)
+
+	printIssueDetailsByType(report.Issues)
+	printAverageByStatus(report.AverageByStatus)
+	printAverageByStatusType(report.AverageByStatusType)
+	printWIP(report.Wip)
+	printThroughput(report.Throughput)
+	printThroughputRunChart(report.ThroughputRunChart)
+	printAverageCycleTime(report.AverageCycleTime)
+	printDistributions(report.Distributions)
+	printFlowEfficiency(report.FlowEfficiency)
+	printBlockedTime(report.BlockedTime)
+	printServiceLevels(report.ServiceLevels)
+	printGroups(report.GroupBy, report.Groups)
+}
+
+func printIssueDetailsByType(issues []IssueSummary) {
+	const separator = " | "
+	var issueType string
+	for _, issue := range issues {
+		if issue.IssueType != issueType || issueType == "" {
+			issueType = issue.IssueType
+			title("\n>> %s\n", issueType)
+		}
+
+		toPrint := color.RedString(issue.Key) + separator
+		toPrint += color.WhiteString(issue.Title) + separator
+		toPrint += color.YellowString("Created: %s", formatBrDate(issue.CreatedDate))
+
+		if issue.WipDate != nil {
+			toPrint += separator
+			toPrint += color.YellowString("To WIP: %s", formatBrDate(*issue.WipDate))
+		}
+
+		if issue.ResolvedDate != nil {
+			toPrint += separator
+			toPrint += color.YellowString("Resolved: %s", formatBrDate(*issue.ResolvedDate))
+		}
+
+		if wipIdle := issue.WipIdle.Value; wipIdle > 1 {
+			toPrint += separator
+			toPrint += color.WhiteString("WIP/Idle: %d", getDisplayDays(wipIdle))
+		}
+
+		if wip := issue.Wip.Value; wip > 1 {
+			toPrint += separator
+			toPrint += color.WhiteString("WIP: %d", getDisplayDays(wip))
+		}
+
+		if issue.FlowEfficiency != nil && issue.WipIdle.Value > 1 {
+			toPrint += separator
+			toPrint += color.WhiteString("Efficiency: %.0f%%", *issue.FlowEfficiency)
+		}
+
+		if issue.FlagDays > 0 {
+			toPrint += separator
+			toPrint += color.WhiteString("Flag: %d", issue.FlagDays)
+		}
+
+		if issue.EpicLink != "" {
+			toPrint += separator
+			toPrint += color.GreenString("Epic: %v", issue.EpicLink)
+		}
+		if len(issue.Labels) > 0 {
+			toPrint += separator
+			toPrint += color.BlueString("Labels: %v", strings.Join(issue.Labels, ", "))
+		}
+		for _, customField := range issue.CustomFields {
+			toPrint += separator
+			toPrint += customField
+		}
+		if issue.Status != "" {
+			toPrint += color.YellowString(" (%s)", issue.Status)
+		}
+		toPrint += "\n"
+		_, _ = fmt.Fprint(color.Output, toPrint)
+	}
+}
+
+// Durations shorter than a day are shown as one day
+func getDisplayDays(duration time.Duration) int {
+	if duration < Calendar.dayLength() {
+		return 1
+	}
+	return getDays(duration)
+}
+
+func printAverageByStatus(statusDurations []StatusDuration) {
+	title("\n> Average by Status\n")
+	printStatusDurations(statusDurations)
+}
+
+func printAverageByStatusType(statusDurations []StatusDuration) {
+	title("\n> Average by Status Type\n")
+	printStatusDurations(statusDurations)
+}
+
+func printStatusDurations(statusDurations []StatusDuration) {
+	for _, statusDuration := range statusDurations {
+		fmt.Printf("%v = %.2f%%", statusDuration.Status, statusDuration.Percent)
+		warn(" [%s]\n", durafmt.Parse(statusDuration.Duration.Value))
+	}
+}
+
+func printWIP(wip WipSummary) {
+	title("\n> WIP/Idle\n")
+	fmt.Printf("Monthly: ")
+	warn("%d tasks were in WIP/Idle\n", wip.Tasks)
+}
+
+func printThroughput(throughput Throughput) {
+	title("\n> Throughput\n")
+	fmt.Printf("Total: ")
+	warn("%d tasks delivered\n", throughput.Total)
+	fmt.Printf("By issue type:\n")
+	for _, typeCount := range throughput.ByType {
+		fmt.Printf("- %v: %v tasks", typeCount.IssueType, typeCount.Count)
+		warn(" (%d%%)\n", int(typeCount.Percent))
+	}
+}
+
+func printAverageCycleTime(cycleTime AverageCycleTime) {
+	title("\n> Average cycle time\n")
+	fmt.Printf("Average: ")
+	warn("%d days\n", getDays(cycleTime.Average.Value))
+	fmt.Printf("By issue type:\n")
+	for _, typeDuration := range cycleTime.ByType {
+		fmt.Printf("- %v: ", typeDuration.IssueType)
+		warn("%d days\n", getDays(typeDuration.Average.Value))
+	}
+}
+
+func printDistributions(distributions []TypeDistribution) {
+	if len(distributions) == 0 {
+		return
+	}
+	title("\n> Lead time distribution (days)\n")
+	printDistributionHeader()
+	for _, typeDistribution := range distributions {
+		printDistribution(typeDistribution.IssueType, typeDistribution.LeadTime)
+	}
+	title("\n> Cycle time distribution (days)\n")
+	printDistributionHeader()
+	for _, typeDistribution := range distributions {
+		printDistribution(typeDistribution.IssueType, typeDistribution.CycleTime)
+	}
+}
+
+func printDistributionHeader() {
+	fmt.Printf("%-15s %6s %7s", "Type", "Count", "Min")
+	for _, percentile := range distributionPercentiles {
+		fmt.Printf(" %6d%%", percentile)
+	}
+	fmt.Printf(" %7s %7s %7s\n", "Max", "Mean", "StdDev")
+}
+
+func printDistribution(issueType string, distribution Distribution) {
+	fmt.Printf("%-15s %6d %7.1f", issueType, distribution.Count, distribution.Min.Days)
+	for _, percentile := range distribution.Percentiles {
+		warn(" %7.1f", percentile.Duration.Days)
+	}
+	fmt.Printf(" %7.1f %7.1f %7.1f\n", distribution.Max.Days, distribution.Mean.Days, distribution.StdDev.Days)
+}
+
+func printServiceLevels(serviceLevels []ServiceLevelResult) {
+	if len(serviceLevels) == 0 {
+		return
+	}
+	title("\n> Service level expectations\n")
+	for _, serviceLevel := range serviceLevels {
+		fmt.Printf("%v: %.2f%% within (%d of %d)", serviceLevel.ServiceLevel, serviceLevel.Percent,
+			serviceLevel.Within, serviceLevel.Resolved)
+		if serviceLevel.Resolved == 0 {
+			fmt.Printf(" no resolved issues\n")
+		} else if serviceLevel.Met {
+			info(" met\n")
+		} else {
+			warn(" breached\n")
+		}
+		for _, breach := range serviceLevel.Breaches {
+			fmt.Printf("- %v: ", breach.Key)
+			warn("%.1f days\n", breach.CycleTime.Days)
+		}
+	}
+}
+
+func printGroups(groupBy string, groups []GroupMetrics) {
+	if len(groups) == 0 {
+		return
+	}
+	title("\n> By %s\n", groupBy)
+	for _, group := range groups {
+		title("\n>> %s (%d issues)\n", group.Group, group.Issues)
+		fmt.Printf("Throughput: ")
+		warn("%d tasks delivered\n", group.Throughput)
+		fmt.Printf("Cycle time: ")
+		warn("%d days\n", getDays(group.CycleTime.Value))
+		fmt.Printf("WIP/Idle: ")
+		warn("%d tasks\n", group.Wip.Tasks)
+		fmt.Printf("Status types: ")
+		printInlineStatusDurations(group.AverageByStatusType)
+		fmt.Printf("Statuses: ")
+		printInlineStatusDurations(group.AverageByStatus)
+	}
+}
+
+func printInlineStatusDurations(statusDurations []StatusDuration) {
+	for i, statusDuration := range statusDurations {
+		if i > 0 {
+			fmt.Printf(" | ")
+		}
+		fmt.Printf("%v ", statusDuration.Status)
+		warn("%.2f%%", statusDuration.Percent)
+	}
+	fmt.Println()
+}
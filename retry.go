@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	initialRetryBackoff = time.Second
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// Error of a Jira call that failed for good, holding the error of every attempt
+type jiraCallError struct {
+	description string
+	attempts    []string
+	// status code of the last attempt, zero when there was no response
+	statusCode int
+}
+
+func (e *jiraCallError) Error() string {
+	message := fmt.Sprintf("%v failed after %d attempt(s)", e.description, len(e.attempts))
+	for i, attempt := range e.attempts {
+		message += fmt.Sprintf("\n  attempt %d: %v", i+1, attempt)
+	}
+	return message
+}
+
+// Runs a Jira call retrying transient failures (timeouts, reset connections, 5xx and 429 responses)
+// with exponential backoff, waiting for the Retry-After header of throttled responses
+func callJira(description string, call func() (*jira.Response, error)) error {
+	maxRetries := parseNonNegativeInt("--retries", CLParameters.Retries)
+	backoff := initialRetryBackoff
+	callError := &jiraCallError{description: description}
+	for {
+		resp, err := call()
+		if err == nil {
+			return nil
+		}
+		callError.attempts = append(callError.attempts, describeJiraError(resp, err))
+		if resp != nil && resp.Response != nil {
+			callError.statusCode = resp.StatusCode
+		}
+		if len(callError.attempts) > maxRetries || !isTransientError(resp, err) {
+			return callError
+		}
+
+		wait := getRetryAfter(resp)
+		if wait == 0 {
+			// jitter keeps concurrent workers from retrying at the same time
+			wait = backoff/2 + time.Duration(rand.Int63n(int64(backoff)))
+			backoff *= 2
+			if backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
+		}
+		if CLParameters.Debug {
+			log.Printf("Retrying %v in %v: %v", description, wait, err)
+		}
+		time.Sleep(wait)
+	}
+}
+
+// Throttled and 5xx responses, timeouts and reset connections are worth retrying. Other failures without a
+// response, like an invalid URL or a failed certificate verification, would fail the same way again.
+func isTransientError(resp *jira.Response, err error) bool {
+	if resp != nil && resp.Response != nil {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	}
+	for err != nil {
+		if netErr, ok := err.(net.Error); ok && (netErr.Timeout() || netErr.Temporary()) {
+			return true
+		}
+		if err == syscall.ECONNRESET || err == io.EOF || err == io.ErrUnexpectedEOF {
+			return true
+		}
+		// go-jira wraps the errors of the HTTP client with pkg/errors, which only exposes Cause
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() error }:
+			err = wrapper.Unwrap()
+		case interface{ Cause() error }:
+			err = wrapper.Cause()
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Retry-After holds either a number of seconds or an HTTP date
+func getRetryAfter(resp *jira.Response) time.Duration {
+	if resp == nil || resp.Response == nil {
+		return 0
+	}
+	retryAfter := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if retryAfter == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil && date.After(time.Now()) {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func describeJiraError(resp *jira.Response, err error) string {
+	if resp == nil || resp.Response == nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v: %v", resp.Status, err)
+}
+
+func parseNonNegativeInt(option string, value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid %v value %v, it must be zero or a positive number", option, value)
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+)
+
+// Wraps an error the way pkg/errors does, exposing it through Cause only
+type causeError struct {
+	cause error
+}
+
+func (e causeError) Error() string { return "No response returned: " + e.cause.Error() }
+func (e causeError) Cause() error  { return e.cause }
+
+func TestIsTransientError(t *testing.T) {
+	response := func(statusCode int) *jira.Response {
+		return &jira.Response{Response: &http.Response{StatusCode: statusCode}}
+	}
+	urlError := func(err error) error {
+		return causeError{&url.Error{Op: "Get", URL: "https://jira.example.com", Err: err}}
+	}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	tests := []struct {
+		name string
+		resp *jira.Response
+		err  error
+		want bool
+	}{
+		{"throttled", response(http.StatusTooManyRequests), errors.New("429"), true},
+		{"server error", response(http.StatusBadGateway), errors.New("502"), true},
+		{"unauthorized", response(http.StatusUnauthorized), errors.New("401"), false},
+		{"timeout", nil, urlError(&net.DNSError{Err: "i/o timeout", IsTimeout: true}), true},
+		{"connection reset", nil, urlError(reset), true},
+		{"connection closed", nil, urlError(fmt.Errorf("reading response: %w", io.EOF)), true},
+		{"unknown host", nil, urlError(&net.DNSError{Err: "no such host", IsNotFound: true}), false},
+		{"invalid URL", nil, urlError(errors.New("unsupported protocol scheme")), false},
+	}
+	for _, test := range tests {
+		if got := isTransientError(test.resp, test.err); got != test.want {
+			t.Errorf("%v: isTransientError = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCallJiraCertificateError(t *testing.T) {
+	var requests int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	CLParameters.Retries = "3"
+	defer func() { CLParameters.Retries = "" }()
+	client, err := jira.NewClient(&http.Client{}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = callJira("get PRJ-1", func() (*jira.Response, error) {
+		_, resp, err := client.Issue.Get("PRJ-1", nil)
+		return resp, err
+	})
+	var callError *jiraCallError
+	if !errors.As(err, &callError) || len(callError.attempts) != 1 {
+		t.Errorf("callJira = %v, want a single attempt", err)
+	}
+	if requests != 0 {
+		t.Errorf("server got %d requests, want none", requests)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Supported values of --interval
+const (
+	dayInterval   = "day"
+	weekInterval  = "week"
+	monthInterval = "month"
+)
+
+// Number of intervals averaged by the rolling average, the current one included
+const rollingAverageWindow = 3
+
+// Relative change over the whole run chart under which the throughput is considered flat
+const flatTrendThreshold = 0.1
+
+const (
+	risingTrend  = "rising"
+	fallingTrend = "falling"
+	flatTrend    = "flat"
+)
+
+type ThroughputInterval struct {
+	Start          time.Time
+	End            time.Time
+	Total          int
+	ByType         map[string]int
+	RollingAverage float64
+}
+
+type ThroughputRunChart struct {
+	Interval  string
+	Intervals []ThroughputInterval
+	// Least squares slope of the totals, in tasks per interval
+	Slope float64
+	Trend string
+}
+
+// Buckets the resolved issues by working day, week or month between startDate and endDate
+func getThroughputRunChart(issueDetails []IssueDetails, startDate, endDate time.Time, interval string) ThroughputRunChart {
+	runChart := ThroughputRunChart{Interval: interval}
+	starts := getIntervalStarts(startDate, endDate, interval)
+	for i, start := range starts {
+		// each interval lasts until the next one, so issues resolved on non working days count in the previous day
+		end := endDate
+		if i+1 < len(starts) {
+			end = starts[i+1].AddDate(0, 0, -1)
+		}
+		runChart.Intervals = append(runChart.Intervals, ThroughputInterval{
+			Start:  start,
+			End:    end,
+			ByType: make(map[string]int),
+		})
+	}
+
+	for _, issueDetails := range issueDetails {
+		if issueDetails.ResolvedDate.IsZero() {
+			continue
+		}
+		resolvedDay := parseDate(formatBrDate(issueDetails.ResolvedDate))
+		for i := range runChart.Intervals {
+			bucket := &runChart.Intervals[i]
+			if !resolvedDay.Before(bucket.Start) && !resolvedDay.After(bucket.End) {
+				bucket.Total++
+				bucket.ByType[issueDetails.IssueType]++
+				break
+			}
+		}
+	}
+
+	var totals []float64
+	for i := range runChart.Intervals {
+		totals = append(totals, float64(runChart.Intervals[i].Total))
+		window := totals
+		if len(window) > rollingAverageWindow {
+			window = window[len(window)-rollingAverageWindow:]
+		}
+		runChart.Intervals[i].RollingAverage = roundFloat(mean(window))
+	}
+	runChart.Slope = roundFloat(getSlope(totals))
+	runChart.Trend = getTrend(totals, runChart.Slope)
+	return runChart
+}
+
+func getIntervalStarts(startDate, endDate time.Time, interval string) []time.Time {
+	if interval == dayInterval {
+		starts := getWorkingDays(startDate, endDate)
+		if len(starts) > 0 {
+			starts[0] = startDate
+		}
+		return starts
+	}
+
+	var starts []time.Time
+	for start := startDate; !start.After(endDate); start = getIntervalEnd(start, interval).AddDate(0, 0, 1) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// Last day of the interval starting at start, weeks ending on Sunday
+func getIntervalEnd(start time.Time, interval string) time.Time {
+	switch interval {
+	case weekInterval:
+		return start.AddDate(0, 0, (7-int(start.Weekday()))%7)
+	case monthInterval:
+		return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location()).AddDate(0, 0, -1)
+	default:
+		return start
+	}
+}
+
+func parseInterval(interval string) string {
+	interval = strings.ToLower(interval)
+	if interval != dayInterval && interval != weekInterval && interval != monthInterval {
+		log.Fatalf("Invalid --interval %v, expected %v, %v or %v", interval, dayInterval, weekInterval, monthInterval)
+	}
+	return interval
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+func getSlope(values []float64) float64 {
+	n := float64(len(values))
+	if n < 2 {
+		return 0
+	}
+	xMean, yMean := (n-1)/2, mean(values)
+	var numerator, denominator float64
+	for i, value := range values {
+		numerator += (float64(i) - xMean) * (value - yMean)
+		denominator += (float64(i) - xMean) * (float64(i) - xMean)
+	}
+	return numerator / denominator
+}
+
+// The trend is flat when the change predicted by the slope over the run chart is under 10% of the mean
+func getTrend(values []float64, slope float64) string {
+	change := slope * float64(len(values)-1)
+	average := mean(values)
+	if average == 0 || math.Abs(change) < flatTrendThreshold*average {
+		return flatTrend
+	}
+	if change > 0 {
+		return risingTrend
+	}
+	return fallingTrend
+}
+
+// Symbols of the issue types in the stacked bars, repeated when there are more types
+var runChartSymbols = []string{"#", "=", "*", "+", "%", "@", "~", "o"}
+
+// Width of the longest bar of the chart in characters
+const runChartWidth = 50
+
+func printThroughputRunChart(runChart ThroughputRunChart) {
+	if len(runChart.Intervals) == 0 {
+		return
+	}
+	title("\n> Throughput by %s\n", runChart.Interval)
+
+	issueTypesMap := make(map[string]int)
+	maxTotal := 0
+	for _, interval := range runChart.Intervals {
+		for issueType, count := range interval.ByType {
+			issueTypesMap[issueType] += count
+		}
+		if interval.Total > maxTotal {
+			maxTotal = interval.Total
+		}
+	}
+	issueTypes := getSortedKeys(issueTypesMap)
+	symbols := make(map[string]string)
+	for i, issueType := range issueTypes {
+		symbols[issueType] = runChartSymbols[i%len(runChartSymbols)]
+		fmt.Printf("%s %s  ", symbols[issueType], issueType)
+	}
+	fmt.Println()
+
+	scale := 1.0
+	if maxTotal > runChartWidth {
+		scale = float64(runChartWidth) / float64(maxTotal)
+	}
+	for _, interval := range runChart.Intervals {
+		var bar string
+		for _, issueType := range issueTypes {
+			bar += strings.Repeat(symbols[issueType], int(math.Round(float64(interval.ByType[issueType])*scale)))
+		}
+		fmt.Printf("%s  ", formatBrDate(interval.Start))
+		warn("%-*s", int(math.Round(float64(maxTotal)*scale)), bar)
+		fmt.Printf(" %3d  avg %.1f\n", interval.Total, interval.RollingAverage)
+	}
+
+	fmt.Printf("Trend: ")
+	switch runChart.Trend {
+	case risingTrend:
+		info("rising")
+	case fallingTrend:
+		warn("falling")
+	default:
+		fmt.Printf("flat")
+	}
+	fmt.Printf(" (%+.2f tasks per %s)\n", runChart.Slope, runChart.Interval)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/andygrunwald/go-jira"
+	"log"
+	"os"
+)
+
+// Snapshots hold the raw issues returned by searchIssues, changelog included,
+// so the same data can be replayed with a different board configuration without querying Jira.
+func saveSnapshot(fileName string, issues []jira.Issue) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		log.Fatalf("Failed to create snapshot file %v: %v", fileName, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	err = encoder.Encode(issues)
+	if err != nil {
+		log.Fatalf("Failed to write snapshot file %v: %v", fileName, err)
+	}
+	if CLParameters.Debug {
+		log.Printf("Saved %v issues to snapshot %v", len(issues), fileName)
+	}
+}
+
+func loadSnapshot(fileName string) []jira.Issue {
+	file, err := os.Open(fileName)
+	if err != nil {
+		log.Fatalf("Failed to open snapshot file %v: %v", fileName, err)
+	}
+	defer file.Close()
+
+	var issues []jira.Issue
+	decoder := json.NewDecoder(file)
+	err = decoder.Decode(&issues)
+	if err != nil {
+		log.Fatalf("Failed to decode snapshot file %v: %v", fileName, err)
+	}
+	if CLParameters.Debug {
+		log.Printf("Loaded %v issues from snapshot %v", len(issues), fileName)
+	}
+	return issues
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testSnapshot = "testdata/snapshot.json"
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	issues := loadSnapshot(testSnapshot)
+	if len(issues) != 3 {
+		t.Fatalf("loaded %d issues, want 3", len(issues))
+	}
+
+	fileName := filepath.Join(dir, "snapshot.json")
+	saveSnapshot(fileName, issues)
+	replayed := loadSnapshot(fileName)
+	if !reflect.DeepEqual(issues, replayed) {
+		t.Errorf("replayed snapshot differs from the saved issues")
+	}
+}
+
+func TestSnapshotIssueDetails(t *testing.T) {
+	BoardCfg = BoardConfig{
+		OpenStatus: []string{"Open"},
+		WipStatus:  []string{"In Progress"},
+		DoneStatus: []string{"Done"},
+	}
+	loadCalendar()
+
+	issueDetails := getIssueDetailsList(loadSnapshot(testSnapshot), parseDate("30/06/2019"))
+	if len(issueDetails) != 3 {
+		t.Fatalf("got %d issue details, want 3", len(issueDetails))
+	}
+
+	location := time.FixedZone("", -3*60*60)
+	resolved := issueDetails[0]
+	if want := time.Date(2019, 6, 4, 9, 0, 0, 0, location); !resolved.WipDate.Equal(want) {
+		t.Errorf("%v WipDate = %v, want %v", resolved.Key, resolved.WipDate, want)
+	}
+	if want := time.Date(2019, 6, 6, 9, 0, 0, 0, location); !resolved.ResolvedDate.Equal(want) {
+		t.Errorf("%v ResolvedDate = %v, want %v", resolved.Key, resolved.ResolvedDate, want)
+	}
+	if got := resolved.GetWipTotalDuration(); got != 48*time.Hour {
+		t.Errorf("%v WIP duration = %v, want 48h", resolved.Key, got)
+	}
+
+	flagged := issueDetails[1]
+	if !flagged.ResolvedDate.IsZero() {
+		t.Errorf("%v ResolvedDate = %v, want none", flagged.Key, flagged.ResolvedDate)
+	}
+	if len(flagged.FlagDetails) != 1 || flagged.FlagDetails[0].FlagEnd.Sub(flagged.FlagDetails[0].FlagStart) != 24*time.Hour {
+		t.Errorf("%v FlagDetails = %v, want one day flagged", flagged.Key, flagged.FlagDetails)
+	}
+
+	open := issueDetails[2]
+	if status := open.TransitionDetails.StatusTo; status != "Open" {
+		t.Errorf("%v status = %v, want Open", open.Key, status)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+var distributionPercentiles = []int{50, 70, 85, 95}
+
+type PercentileDuration struct {
+	Percentile int
+	Duration   ReportDuration
+}
+
+type Distribution struct {
+	Count       int
+	Min         ReportDuration
+	Max         ReportDuration
+	Mean        ReportDuration
+	StdDev      ReportDuration
+	Percentiles []PercentileDuration
+}
+
+func getDistribution(durations []time.Duration) Distribution {
+	distribution := Distribution{Count: len(durations)}
+	if len(durations) == 0 {
+		return distribution
+	}
+
+	sorted := sortDurations(durations)
+	var total time.Duration
+	for _, duration := range sorted {
+		total += duration
+	}
+	mean := total / time.Duration(len(sorted))
+
+	var squaredDiffs float64
+	for _, duration := range sorted {
+		diff := float64(duration - mean)
+		squaredDiffs += diff * diff
+	}
+
+	distribution.Min = newReportDuration(sorted[0])
+	distribution.Max = newReportDuration(sorted[len(sorted)-1])
+	distribution.Mean = newReportDuration(mean)
+	distribution.StdDev = newReportDuration(time.Duration(math.Sqrt(squaredDiffs / float64(len(sorted)))))
+	for _, percentile := range distributionPercentiles {
+		distribution.Percentiles = append(distribution.Percentiles, PercentileDuration{
+			Percentile: percentile,
+			Duration:   newReportDuration(getPercentile(sorted, percentile)),
+		})
+	}
+	return distribution
+}
+
+func sortDurations(durations []time.Duration) []time.Duration {
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// Nearest-rank percentile of an ascending sorted list
+func getPercentile(sorted []time.Duration, percentile int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(percentile) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Same as getPercentile, for an ascending sorted list of ints
+func getIntPercentile(sorted []int, percentile int) int {
+	durations := make([]time.Duration, len(sorted))
+	for i, value := range sorted {
+		durations[i] = time.Duration(value)
+	}
+	return int(getPercentile(durations, percentile))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetPercentile(t *testing.T) {
+	days := func(values ...int) []time.Duration {
+		var durations []time.Duration
+		for _, value := range values {
+			durations = append(durations, time.Duration(value)*24*time.Hour)
+		}
+		return durations
+	}
+	tests := []struct {
+		name       string
+		sorted     []time.Duration
+		percentile int
+		want       time.Duration
+	}{
+		{"empty", nil, 85, 0},
+		{"single", days(3), 50, days(3)[0]},
+		{"median of even", days(1, 2, 3, 4), 50, days(2)[0]},
+		{"85th of ten", days(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 85, days(9)[0]},
+		{"95th of ten", days(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 95, days(10)[0]},
+		{"zero percentile", days(1, 2, 3), 0, days(1)[0]},
+	}
+	for _, test := range tests {
+		if got := getPercentile(test.sorted, test.percentile); got != test.want {
+			t.Errorf("%v: getPercentile = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetIntPercentile(t *testing.T) {
+	tests := []struct {
+		sorted     []int
+		percentile int
+		want       int
+	}{
+		{nil, 50, 0},
+		{[]int{7}, 95, 7},
+		{[]int{1, 2, 3, 4, 5}, 50, 3},
+		{[]int{1, 2, 3, 4, 5}, 70, 4},
+		{[]int{1, 2, 3, 4, 5}, 100, 5},
+	}
+	for _, test := range tests {
+		if got := getIntPercentile(test.sorted, test.percentile); got != test.want {
+			t.Errorf("getIntPercentile(%v, %d) = %d, want %d", test.sorted, test.percentile, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andygrunwald/go-jira"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	openStatusType      = "Open"
+	wipStatusType       = "Wip"
+	idleStatusType      = "Idle"
+	doneStatusType      = "Done"
+	notMappedStatusType = "Not Mapped"
+)
+
+// Jira status category keys
+const (
+	toDoStatusCategory       = "new"
+	inProgressStatusCategory = "indeterminate"
+	doneStatusCategory       = "done"
+)
+
+type StatusInfo struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"-"`
+}
+
+// Statuses seen in the issues or returned by Jira, used to match the board config by status ID
+// and to fall back on the status category
+var knownStatusesByName = make(map[string]StatusInfo)
+var knownStatusesById = make(map[string]StatusInfo)
+
+func addKnownStatus(id, name, category string) {
+	if id == "" || name == "" {
+		return
+	}
+	status := knownStatusesById[id]
+	status.Id, status.Name = id, name
+	if category != "" {
+		status.Category = category
+	}
+	knownStatusesById[id] = status
+	knownStatusesByName[strings.ToUpper(name)] = status
+}
+
+func getKnownStatus(name string) StatusInfo {
+	return knownStatusesByName[strings.ToUpper(name)]
+}
+
+// Collects the ID and name of every status in the changelog, and the category of the current status
+func addIssueStatuses(issues []jira.Issue) {
+	for _, issue := range issues {
+		if issue.Changelog != nil {
+			for _, history := range issue.Changelog.Histories {
+				for _, item := range history.Items {
+					if item.Field == "status" {
+						addKnownStatus(getChangelogItemId(item.From), item.FromString, "")
+						addKnownStatus(getChangelogItemId(item.To), item.ToString, "")
+					}
+				}
+			}
+		}
+		if issue.Fields != nil && issue.Fields.Status != nil {
+			status := issue.Fields.Status
+			addKnownStatus(status.ID, status.Name, status.StatusCategory.Key)
+		}
+	}
+}
+
+func getChangelogItemId(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+type jiraStatus struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+// Loads every status of the Jira instance with its category
+func loadJiraStatuses() {
+	var statuses []jiraStatus
+	req, err := JiraClient.NewRequest("GET", "rest/api/2/status", nil)
+	if err == nil {
+		err = callJira("statuses", func() (*jira.Response, error) {
+			resp, err := JiraClient.Do(req, &statuses)
+			if err != nil {
+				return resp, jira.NewJiraError(resp, err)
+			}
+			return resp, nil
+		})
+	}
+	if err != nil {
+		log.Fatalf("Failed to get statuses from jira: %v", err)
+	}
+	for _, status := range statuses {
+		addKnownStatus(status.Id, status.Name, status.StatusCategory.Key)
+	}
+}
+
+func getStatusTypeByCategory(category string) string {
+	switch category {
+	case toDoStatusCategory:
+		return openStatusType
+	case inProgressStatusCategory:
+		return wipStatusType
+	case doneStatusCategory:
+		return doneStatusType
+	default:
+		return notMappedStatusType
+	}
+}
+
+type projectIssueTypeStatuses struct {
+	Name     string       `json:"name"`
+	Statuses []jiraStatus `json:"statuses"`
+}
+
+// Lists the statuses of every workflow of the project with their ID, category and board mapping
+func printStatuses() {
+	var issueTypes []projectIssueTypeStatuses
+	u := fmt.Sprintf("rest/api/2/project/%s/statuses", BoardCfg.Project)
+	req, err := JiraClient.NewRequest("GET", u, nil)
+	if err == nil {
+		err = callJira("project statuses", func() (*jira.Response, error) {
+			resp, err := JiraClient.Do(req, &issueTypes)
+			if err != nil {
+				return resp, jira.NewJiraError(resp, err)
+			}
+			return resp, nil
+		})
+	}
+	if err != nil {
+		log.Fatalf("Failed to get statuses of project %v from jira: %v", BoardCfg.Project, err)
+	}
+
+	statusesById := make(map[string]jiraStatus)
+	issueTypesById := make(map[string][]string)
+	for _, issueType := range issueTypes {
+		for _, status := range issueType.Statuses {
+			addKnownStatus(status.Id, status.Name, status.StatusCategory.Key)
+			statusesById[status.Id] = status
+			issueTypesById[status.Id] = append(issueTypesById[status.Id], issueType.Name)
+		}
+	}
+	var statuses []jiraStatus
+	for _, status := range statusesById {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	title("Statuses of project %s\n", BoardCfg.Project)
+	fmt.Printf("%-8s %-25s %-15s %-15s %s\n", "ID", "Name", "Category", "Mapping", "Issue types")
+	for _, status := range statuses {
+		mapping := getIssueTypeByStatus(status.Name)
+		if isMappedByCategory(status.Name) {
+			mapping += " (category)"
+		}
+		fmt.Printf("%-8s %-25s %-15s ", status.Id, status.Name, status.StatusCategory.Key)
+		if mapping == notMappedStatusType {
+			warn("%-15s", mapping)
+		} else {
+			info("%-15s", mapping)
+		}
+		fmt.Printf(" %s\n", strings.Join(issueTypesById[status.Id], ", "))
+	}
+}
+
+func isMappedByCategory(status string) bool {
+	return BoardCfg.StatusCategoryFallback && getConfiguredStatusType(status) == notMappedStatusType &&
+		getIssueTypeByStatus(status) != notMappedStatusType
+}
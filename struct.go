@@ -1,27 +1,96 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/hako/durafmt"
+	"github.com/zchee/color"
+	"strings"
 	"time"
 )
 
 var CLParameters struct {
-	StartDate string `docopt:"<start>"`
-	EndDate   string `docopt:"<end>"`
-	Jql       string `docopt:"<JQL>"`
-	Debug     bool
+	Cfd              bool   `docopt:"cfd"`
+	Forecast         bool   `docopt:"forecast"`
+	Aging            bool   `docopt:"aging"`
+	Cache            bool   `docopt:"cache"`
+	Statuses         bool   `docopt:"statuses"`
+	Init             bool   `docopt:"init"`
+	Validate         bool   `docopt:"validate"`
+	CacheStatus      bool   `docopt:"status"`
+	CacheClear       bool   `docopt:"clear"`
+	StartDate        string `docopt:"<start>"`
+	EndDate          string `docopt:"<end>"`
+	Jql              string `docopt:"<JQL>"`
+	SaveSnapshot     string `docopt:"--save-snapshot"`
+	FromSnapshot     string `docopt:"--from-snapshot"`
+	Format           string `docopt:"--format"`
+	Output           string `docopt:"--output"`
+	Config           string `docopt:"--config"`
+	Board            string `docopt:"--board"`
+	BoardId          string `docopt:"--board-id"`
+	Workers          string `docopt:"--workers"`
+	UseCache         bool   `docopt:"--cache"`
+	Retries          string `docopt:"--retries"`
+	Timeout          string `docopt:"--timeout"`
+	Items            string `docopt:"--items"`
+	Until            string `docopt:"--until"`
+	Iterations       string `docopt:"--iterations"`
+	Confidence       string `docopt:"--confidence"`
+	GroupBy          string `docopt:"--group-by"`
+	Interval         string `docopt:"--interval"`
+	Filter           string `docopt:"--filter"`
+	FlaggedAsWaiting bool   `docopt:"--flagged-as-waiting"`
+	Debug            bool
 }
 
-var BoardCfg struct {
-	JiraUrl    string
-	Login      string
-	Password   string
-	Project    string
-	OpenStatus []string
-	WipStatus  []string
-	IdleStatus []string
-	DoneStatus []string
+var BoardCfg BoardConfig
+
+type BoardConfig struct {
+	JiraUrl       string
+	AuthType      string
+	Login         string
+	Password      string
+	SecretEnv     string
+	SecretCommand string
+	TLS           TLSCfg
+	Proxy         string
+	Project       string
+	OpenStatus    []string
+	WipStatus     []string
+	IdleStatus    []string
+	DoneStatus    []string
+	// Maps statuses missing from the lists above by their Jira status category
+	StatusCategoryFallback bool
+	ServiceLevels          []ServiceLevel
+	CustomFields           []CustomFieldCfg
+	Calendar               CalendarCfg
+	Jql                    string
+	DefaultBoard           string
+	Boards                 map[string]json.RawMessage
+	Board                  string `json:"-"`
+}
+
+const allIssueTypes = "All"
+
+// ServiceLevel is a service level expectation such as "85% of Stories in 8 days".
+// An empty IssueType applies it to every issue type.
+type ServiceLevel struct {
+	IssueType  string `json:",omitempty"`
+	Percentile float64
+	Days       float64
+}
+
+func (s ServiceLevel) appliesTo(issueType string) bool {
+	return s.IssueType == "" || strings.ToUpper(s.IssueType) == strings.ToUpper(issueType)
+}
+
+func (s ServiceLevel) String() string {
+	issueType := s.IssueType
+	if issueType == "" {
+		issueType = "issues"
+	}
+	return fmt.Sprintf("%v%% of %v in %v days", s.Percentile, issueType, s.Days)
 }
 
 type IssueDetails struct {
@@ -34,6 +103,9 @@ type IssueDetails struct {
 	EpicLink          string
 	Sprint            string
 	Labels            []string
+	Assignee          string
+	Priority          string
+	Components        []string
 	CustomFields      []CustomField
 	TransitionDetails *TransitionDetails
 	FlagDetails       []FlagDetails
@@ -45,19 +117,52 @@ type FlagDetails struct {
 	FlagEnd   time.Time
 }
 
-func (f *FlagDetails) GetFlagDuration() time.Duration {
+// Returns when the flag was removed, or windowEnd when it is still set
+func (f FlagDetails) getEnd(windowEnd time.Time) time.Time {
 	if f.FlagEnd.IsZero() {
-		f.FlagEnd = parseDate(CLParameters.EndDate)
+		return windowEnd
+	}
+	return f.FlagEnd
+}
+
+func (f FlagDetails) isSetAt(t time.Time) bool {
+	return f.FlagStart.Before(t) && (f.FlagEnd.IsZero() || f.FlagEnd.After(t))
+}
+
+// Returns the flagged time until windowEnd, a flag still set counting until windowEnd
+func (f FlagDetails) GetFlagDuration(windowEnd time.Time) time.Duration {
+	end := f.getEnd(windowEnd)
+	if end.After(windowEnd) {
+		end = windowEnd
 	}
-	return getTransitionDuration(f.FlagStart, f.FlagEnd)
+	if !end.After(f.FlagStart) {
+		return 0
+	}
+	return getTransitionDuration(f.FlagStart, end)
+}
+
+// Returns whether the issue was flagged at the given time
+func (i *IssueDetails) IsFlaggedAt(t time.Time) bool {
+	for _, flag := range i.FlagDetails {
+		if flag.isSetAt(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *IssueDetails) GetLeadTimeDuration() time.Duration {
+	if i.ResolvedDate.IsZero() {
+		return 0
+	}
+	return getTransitionDuration(i.CreatedDate, i.ResolvedDate)
 }
 
 func (i *IssueDetails) GetWipAndIdleTotalDuration() time.Duration {
 	var wipIdleTotal time.Duration
-	var wipIdleStatus = append(BoardCfg.WipStatus, BoardCfg.IdleStatus...)
 	var currentTransition = i.TransitionDetails
 	for {
-		if containsStatus(wipIdleStatus, currentTransition.StatusFrom) {
+		if statusType := getIssueTypeByStatus(currentTransition.StatusFrom); statusType == wipStatusType || statusType == idleStatusType {
 			wipIdleTotal += currentTransition.getTotalDuration()
 		}
 		if currentTransition.PreviousTransition == nil {
@@ -72,7 +177,7 @@ func (i *IssueDetails) GetWipTotalDuration() time.Duration {
 	var wipTotal time.Duration
 	var currentTransition = i.TransitionDetails
 	for {
-		if containsStatus(BoardCfg.WipStatus, currentTransition.StatusFrom) {
+		if getIssueTypeByStatus(currentTransition.StatusFrom) == wipStatusType {
 			wipTotal += currentTransition.getTotalDuration()
 		}
 		if currentTransition.PreviousTransition == nil {
@@ -98,6 +203,21 @@ func (i *IssueDetails) GetDurationByStatus() map[string]time.Duration {
 	return statusMap
 }
 
+// Returns the status the issue was in at the given time, or an empty string if it was not created yet
+func (i *IssueDetails) GetStatusAt(t time.Time) string {
+	if !i.CreatedDate.Before(t) {
+		return ""
+	}
+	var currentTransition = i.TransitionDetails
+	for currentTransition != nil {
+		if currentTransition.Timestamp.Before(t) {
+			return currentTransition.StatusTo
+		}
+		currentTransition = currentTransition.PreviousTransition
+	}
+	return ""
+}
+
 type TransitionDetails struct {
 	Timestamp          time.Time
 	StatusFrom         string
@@ -105,17 +225,22 @@ type TransitionDetails struct {
 	PreviousTransition *TransitionDetails
 }
 
-// Calculates time difference between transitions subtracting weekend days
+// Calculates time difference between transitions subtracting non working days,
+// or counting only working hours when they are configured
 func (t *TransitionDetails) getTotalDuration() time.Duration {
 	return getTransitionDuration(t.PreviousTransition.Timestamp, t.Timestamp)
 }
 
 func getTransitionDuration(firstTransition time.Time, secondTransition time.Time) time.Duration {
+	if Calendar.workingHours && !firstTransition.IsZero() {
+		return Calendar.getWorkingHoursDuration(firstTransition, secondTransition)
+	}
+
 	transitionDuration := secondTransition.Sub(firstTransition)
-	weekendDays := countWeekendDays(firstTransition, secondTransition)
-	if weekendDays > 0 {
-		if getDays(transitionDuration) >= weekendDays {
-			transitionDuration -= time.Duration(weekendDays) * time.Hour * 24
+	nonWorkingDays := countNonWorkingDays(firstTransition, secondTransition)
+	if nonWorkingDays > 0 {
+		if getDays(transitionDuration) >= nonWorkingDays {
+			transitionDuration -= time.Duration(nonWorkingDays) * time.Hour * 24
 		} else {
 			transitionDuration = 0
 		}
@@ -124,13 +249,13 @@ func getTransitionDuration(firstTransition time.Time, secondTransition time.Time
 }
 
 func (t *TransitionDetails) PrintFrom() {
-	info("%s %s\n", formatBrDateWithTime(t.PreviousTransition.Timestamp), t.StatusFrom)
+	debugInfo(color.Error, "%s %s\n", formatBrDateWithTime(t.PreviousTransition.Timestamp), t.StatusFrom)
 }
 
 func (t *TransitionDetails) PrintTo() {
-	info("%s %s", formatBrDateWithTime(t.Timestamp), t.StatusTo)
+	debugInfo(color.Error, "%s %s", formatBrDateWithTime(t.Timestamp), t.StatusTo)
 	if t.PreviousTransition != nil {
-		warn(" [%s]", durafmt.Parse(t.getTotalDuration()))
+		debugWarn(color.Error, " [%s]", durafmt.Parse(t.getTotalDuration()))
 	}
-	fmt.Println()
+	_, _ = fmt.Fprintln(color.Error)
 }
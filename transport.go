@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+type TLSCfg struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	Insecure bool
+}
+
+// Builds the HTTP transport used to reach Jira from the TLS and Proxy settings of the board config.
+// Certificates are verified unless TLS.Insecure is set, and proxies come from HTTPS_PROXY/HTTP_PROXY by default.
+func getTransport() *http.Transport {
+	tlsConfig, err := getTLSConfig(BoardCfg.TLS)
+	if err != nil {
+		log.Fatalf("Invalid TLS config: %v", err)
+	}
+	proxy, err := getProxy(BoardCfg.Proxy)
+	if err != nil {
+		log.Fatalf("Invalid Proxy %v in config file: %v", BoardCfg.Proxy, err)
+	}
+	// keeps the dial and handshake timeouts and the connection pool settings of the default transport
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+func getTLSConfig(cfg TLSCfg) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if cfg.Insecure {
+		log.Printf("Warning: TLS certificate verification is disabled, connections to %v are not secure", BoardCfg.JiraUrl)
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if cfg.CAFile != "" {
+		caFile := getConfigRelativePath(cfg.CAFile)
+		caCerts, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle %v: %v", caFile, err)
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil || certPool == nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %v", caFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, fmt.Errorf("client certificates need both CertFile and KeyFile")
+		}
+		certFile, keyFile := getConfigRelativePath(cfg.CertFile), getConfigRelativePath(cfg.KeyFile)
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %v: %v", certFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+func getProxy(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyURL(proxyUrl), nil
+}
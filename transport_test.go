@@ -0,0 +1,203 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+	fileName := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(fileName, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func newTestDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "transport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// Self-signed client certificate and its key, both PEM encoded
+func newClientCertificate(t *testing.T, commonName string) ([]byte, []byte, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), certificate
+}
+
+func getServerCertificatePEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func getWithTLSConfig(t *testing.T, cfg TLSCfg, url string) (string, error) {
+	t.Helper()
+	tlsConfig, err := getTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("getTLSConfig(%+v) failed: %v", cfg, err)
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	response, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	return string(body), err
+}
+
+func TestTLSServerVerification(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+	caFile := writeTestFile(t, dir, "ca.pem", getServerCertificatePEM(server))
+
+	if _, err := getWithTLSConfig(t, TLSCfg{}, server.URL); err == nil {
+		t.Errorf("default cert pool trusted the test server certificate")
+	}
+	if body, err := getWithTLSConfig(t, TLSCfg{CAFile: caFile}, server.URL); err != nil || body != "ok" {
+		t.Errorf("custom CA bundle: got %q, %v", body, err)
+	}
+	if body, err := getWithTLSConfig(t, TLSCfg{Insecure: true}, server.URL); err != nil || body != "ok" {
+		t.Errorf("insecure: got %q, %v", body, err)
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	certPEM, keyPEM, certificate := newClientCertificate(t, "jira-kanban-metrics")
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(certificate)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := TLSCfg{
+		CAFile:   writeTestFile(t, dir, "ca.pem", getServerCertificatePEM(server)),
+		CertFile: writeTestFile(t, dir, "client.pem", certPEM),
+		KeyFile:  writeTestFile(t, dir, "client.key", keyPEM),
+	}
+	if body, err := getWithTLSConfig(t, cfg, server.URL); err != nil || body != "jira-kanban-metrics" {
+		t.Errorf("client certificate: got %q, %v", body, err)
+	}
+	if _, err := getWithTLSConfig(t, TLSCfg{CAFile: cfg.CAFile}, server.URL); err == nil {
+		t.Errorf("server accepted a connection without client certificate")
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	certPEM, keyPEM, _ := newClientCertificate(t, "jira-kanban-metrics")
+	certFile := writeTestFile(t, dir, "client.pem", certPEM)
+	keyFile := writeTestFile(t, dir, "client.key", keyPEM)
+	notPEM := writeTestFile(t, dir, "not.pem", []byte("not a certificate"))
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name string
+		cfg  TLSCfg
+	}{
+		{"missing CA bundle", TLSCfg{CAFile: missing}},
+		{"CA bundle without certificates", TLSCfg{CAFile: notPEM}},
+		{"certificate without key", TLSCfg{CertFile: certFile}},
+		{"key without certificate", TLSCfg{KeyFile: keyFile}},
+		{"missing certificate", TLSCfg{CertFile: missing, KeyFile: keyFile}},
+		{"invalid certificate", TLSCfg{CertFile: notPEM, KeyFile: keyFile}},
+		{"invalid key", TLSCfg{CertFile: certFile, KeyFile: notPEM}},
+	}
+	for _, test := range tests {
+		if _, err := getTLSConfig(test.cfg); err == nil {
+			t.Errorf("%v: getTLSConfig succeeded, want an error", test.name)
+		}
+	}
+}
+
+func TestGetProxy(t *testing.T) {
+	proxy, err := getProxy("http://proxy.example.com:3128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	request, _ := http.NewRequest("GET", "https://jira.example.com", nil)
+	if proxyUrl, err := proxy(request); err != nil || proxyUrl.Host != "proxy.example.com:3128" {
+		t.Errorf("proxy = %v, %v, want proxy.example.com:3128", proxyUrl, err)
+	}
+	if _, err := getProxy("://proxy"); err == nil {
+		t.Errorf("getProxy accepted an invalid URL")
+	}
+}
+
+func TestGetTransport(t *testing.T) {
+	BoardCfg = BoardConfig{TLS: TLSCfg{Insecure: true}, Proxy: "http://proxy.example.com:3128"}
+	defer func() { BoardCfg = BoardConfig{} }()
+
+	transport := getTransport()
+	if transport.TLSHandshakeTimeout == 0 || transport.IdleConnTimeout == 0 || transport.MaxIdleConns == 0 {
+		t.Errorf("transport lost the timeouts and pool settings of the default transport: %+v", transport)
+	}
+	if transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("TLSClientConfig = %+v, want verification disabled", transport.TLSClientConfig)
+	}
+	request, _ := http.NewRequest("GET", "https://jira.example.com", nil)
+	if proxyUrl, err := transport.Proxy(request); err != nil || proxyUrl.Host != "proxy.example.com:3128" {
+		t.Errorf("proxy = %v, %v, want proxy.example.com:3128", proxyUrl, err)
+	}
+	if tlsConfig := http.DefaultTransport.(*http.Transport).TLSClientConfig; tlsConfig != nil && tlsConfig.InsecureSkipVerify {
+		t.Errorf("getTransport changed the default transport")
+	}
+}
@@ -1,30 +1,45 @@
 package main
 
 import (
-	"github.com/andygrunwald/go-jira"
-	"io/ioutil"
 	"log"
 	"math"
+	"sort"
 	"strings"
 	"time"
 )
 
-func countWeekDays(start, end time.Time) int {
-	var weekDays = 0
+func countWorkingDays(start, end time.Time) int {
+	return len(getWorkingDays(start, end))
+}
+
+func getWorkingDays(start, end time.Time) []time.Time {
+	var workingDays []time.Time
 
 	dateIndex := start
 	for dateIndex.Before(end) || dateIndex.Equal(end) {
-		if dateIndex.Weekday() != time.Saturday && dateIndex.Weekday() != time.Sunday {
-			weekDays++
+		if Calendar.isWorkingDay(dateIndex) {
+			workingDays = append(workingDays, dateIndex)
 		}
 		dateIndex = dateIndex.AddDate(0, 0, 1)
 	}
 
-	return weekDays
+	return workingDays
+}
+
+// Returns the first count working days from start on, however sparse the working calendar is
+func getNextWorkingDays(start time.Time, count int) []time.Time {
+	var workingDays []time.Time
+	for dateIndex := start; len(workingDays) < count; dateIndex = dateIndex.AddDate(0, 0, 1) {
+		if Calendar.isWorkingDay(dateIndex) {
+			workingDays = append(workingDays, dateIndex)
+		}
+	}
+	return workingDays
 }
 
-func countWeekendDays(start time.Time, end time.Time) int {
-	var weekendDays = 0
+// Counts weekend days and holidays according to the working calendar
+func countNonWorkingDays(start time.Time, end time.Time) int {
+	var nonWorkingDays = 0
 
 	if start.IsZero() {
 		return -1
@@ -32,18 +47,29 @@ func countWeekendDays(start time.Time, end time.Time) int {
 
 	dateIndex := start
 	for dateIndex.Before(end) || dateIndex.Equal(end) {
-		if dateIndex.Weekday() == time.Saturday || dateIndex.Weekday() == time.Sunday {
-			weekendDays++
+		if !Calendar.isWorkingDay(dateIndex) {
+			nonWorkingDays++
 		}
 		dateIndex = dateIndex.AddDate(0, 0, 1)
 	}
 
-	return weekendDays
+	return nonWorkingDays
 }
 
+// Status lists match the status name, case insensitively, or its ID
 func containsStatus(statusList []string, status string) bool {
+	statusId := getKnownStatus(status).Id
 	for _, s := range statusList {
-		if strings.ToUpper(s) == strings.ToUpper(status) {
+		if strings.ToUpper(s) == strings.ToUpper(status) || (statusId != "" && s == statusId) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIgnoringCase(values []string, value string) bool {
+	for _, v := range values {
+		if strings.ToUpper(v) == strings.ToUpper(value) {
 			return true
 		}
 	}
@@ -51,39 +77,70 @@ func containsStatus(statusList []string, status string) bool {
 }
 
 func statusIsNotMapped(status string) bool {
-	validStatuses := append(append(append(BoardCfg.OpenStatus, BoardCfg.WipStatus...), BoardCfg.IdleStatus...), BoardCfg.DoneStatus...)
-	for _, validStatus := range validStatuses {
-		if strings.ToUpper(validStatus) == strings.ToUpper(status) {
-			return false
+	return getIssueTypeByStatus(status) == notMappedStatusType
+}
+
+// Returns every status in the board configuration, in Open, Wip, Idle and Done order,
+// with status IDs replaced by their names when known
+func getConfiguredStatuses() []string {
+	var statuses []string
+	for _, statusList := range [][]string{BoardCfg.OpenStatus, BoardCfg.WipStatus, BoardCfg.IdleStatus, BoardCfg.DoneStatus} {
+		for _, status := range statusList {
+			statuses = append(statuses, getStatusDisplayName(status))
 		}
 	}
-	return true
+	return statuses
 }
 
+func getStatusDisplayName(configuredStatus string) string {
+	if status, found := knownStatusesById[configuredStatus]; found {
+		return status.Name
+	}
+	return configuredStatus
+}
+
+// Returns the status as written in the board configuration, or unchanged if it is not mapped
+func getConfiguredStatusName(status string) string {
+	for _, statusList := range [][]string{BoardCfg.OpenStatus, BoardCfg.WipStatus, BoardCfg.IdleStatus, BoardCfg.DoneStatus} {
+		for _, configuredStatus := range statusList {
+			if containsStatus([]string{configuredStatus}, status) {
+				return getStatusDisplayName(configuredStatus)
+			}
+		}
+	}
+	return status
+}
+
+// Maps the status with the board configuration, falling back on the Jira status category when enabled
 func getIssueTypeByStatus(status string) string {
+	statusType := getConfiguredStatusType(status)
+	if statusType == notMappedStatusType && BoardCfg.StatusCategoryFallback {
+		return getStatusTypeByCategory(getKnownStatus(status).Category)
+	}
+	return statusType
+}
+
+func getConfiguredStatusType(status string) string {
 	if containsStatus(BoardCfg.OpenStatus, status) {
-		return "Open"
+		return openStatusType
 	} else if containsStatus(BoardCfg.WipStatus, status) {
-		return "Wip"
+		return wipStatusType
 	} else if containsStatus(BoardCfg.IdleStatus, status) {
-		return "Idle"
+		return idleStatusType
 	} else if containsStatus(BoardCfg.DoneStatus, status) {
-		return "Done"
+		return doneStatusType
 	} else {
-		return "Not Mapped"
+		return notMappedStatusType
 	}
 }
 
 func getDays(duration time.Duration) int {
-	return int(math.Round(duration.Hours() / 24))
+	return int(math.Round(getWorkingDaysFloat(duration)))
 }
 
-func readResponseBody(resp *jira.Response) string {
-	if resp != nil {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return string(body)
-	}
-	return ""
+// Converts a duration into working days according to the working day length of the calendar
+func getWorkingDaysFloat(duration time.Duration) float64 {
+	return duration.Hours() / Calendar.dayLength().Hours()
 }
 
 func parseTime(timeStr string) time.Time {
@@ -94,3 +151,21 @@ func parseTime(timeStr string) time.Time {
 	}
 	return t
 }
+
+func getSortedKeys(m map[string]int) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getSortedIssueTypes(issueDetailsMapByType map[string][]IssueDetails) []string {
+	var issueTypes []string
+	for issueType := range issueDetailsMapByType {
+		issueTypes = append(issueTypes, issueType)
+	}
+	sort.Strings(issueTypes)
+	return issueTypes
+}
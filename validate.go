@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type configValidator struct {
+	problems []string
+	// Fields set by the selected board, reported under Boards.<name>
+	boardFields map[string]bool
+}
+
+// Checks the selected board configuration and returns every problem found, prefixed by the field path
+func validateBoardCfg() []string {
+	v := configValidator{boardFields: getBoardFields()}
+
+	// snapshots are replayed without connecting to Jira
+	if CLParameters.FromSnapshot == "" {
+		if BoardCfg.JiraUrl == "" {
+			v.addProblem("JiraUrl", "is empty")
+		} else if jiraUrl, err := url.Parse(BoardCfg.JiraUrl); err != nil || (jiraUrl.Scheme != "http" && jiraUrl.Scheme != "https") || jiraUrl.Host == "" {
+			v.addProblem("JiraUrl", "%v is not an http or https URL", BoardCfg.JiraUrl)
+		}
+		if authType := getAuthType(); authType != basicAuth && authType != bearerAuth {
+			v.addProblem("AuthType", "%v is unknown, expected %v or %v", BoardCfg.AuthType, basicAuth, bearerAuth)
+		}
+	}
+	if strings.TrimSpace(BoardCfg.Project) == "" && CLParameters.Jql == "" {
+		v.addProblem("Project", "is empty")
+	}
+
+	v.validateStatuses()
+	v.validateServiceLevels()
+	v.validateCustomFields()
+	v.validateCalendar()
+	if CLParameters.FromSnapshot == "" {
+		v.validateConnection()
+	}
+	return v.problems
+}
+
+// Checks the status lists again once the statuses of the issues are known, finding a status mapped twice
+// under its name and its ID
+func validateStatusIds() {
+	v := configValidator{boardFields: getBoardFields()}
+	v.validateStatuses()
+	if len(v.problems) > 0 {
+		log.Fatalf("Invalid config file %v:\n%v", configPath, strings.Join(v.problems, "\n"))
+	}
+}
+
+func (v *configValidator) addProblem(field string, format string, args ...interface{}) {
+	path := field
+	topField := strings.SplitN(strings.SplitN(field, ".", 2)[0], "[", 2)[0]
+	if v.boardFields[strings.ToUpper(topField)] {
+		path = fmt.Sprintf("Boards.%v.%v", BoardCfg.Board, field)
+	}
+	v.problems = append(v.problems, fmt.Sprintf("%v: %v", path, fmt.Sprintf(format, args...)))
+}
+
+func getBoardFields() map[string]bool {
+	fields := make(map[string]bool)
+	if BoardCfg.Board == "" {
+		return fields
+	}
+	var board map[string]json.RawMessage
+	if json.Unmarshal(BoardCfg.Boards[BoardCfg.Board], &board) == nil {
+		// json field names match case insensitively
+		for field := range board {
+			fields[strings.ToUpper(field)] = true
+		}
+	}
+	return fields
+}
+
+// Every status must be mapped once, and Wip and Done statuses are needed unless the status category fallback is on
+func (v *configValidator) validateStatuses() {
+	statusLists := []struct {
+		field    string
+		statuses []string
+	}{
+		{"OpenStatus", BoardCfg.OpenStatus},
+		{"WipStatus", BoardCfg.WipStatus},
+		{"IdleStatus", BoardCfg.IdleStatus},
+		{"DoneStatus", BoardCfg.DoneStatus},
+	}
+
+	firstField := make(map[string]string)
+	for _, statusList := range statusLists {
+		for i, status := range statusList.statuses {
+			field := fmt.Sprintf("%v[%d]", statusList.field, i)
+			key := getStatusKey(status)
+			if key == "" {
+				v.addProblem(field, "status is empty")
+			} else if first, found := firstField[key]; found {
+				v.addProblem(field, "status %v is already mapped in %v", status, first)
+			} else {
+				firstField[key] = field
+			}
+		}
+	}
+
+	if !BoardCfg.StatusCategoryFallback {
+		if len(BoardCfg.WipStatus) == 0 {
+			v.addProblem("WipStatus", "is empty, cycle times can not be measured")
+		}
+		if len(BoardCfg.DoneStatus) == 0 {
+			v.addProblem("DoneStatus", "is empty, no issue can be resolved")
+		}
+	}
+}
+
+// Status ID of a status of the config when it is known, otherwise its name ignoring case
+func getStatusKey(status string) string {
+	status = strings.TrimSpace(status)
+	if _, found := knownStatusesById[status]; found {
+		return status
+	}
+	if known := getKnownStatus(status); known.Id != "" {
+		return known.Id
+	}
+	return strings.ToUpper(status)
+}
+
+func (v *configValidator) validateServiceLevels() {
+	for i, serviceLevel := range BoardCfg.ServiceLevels {
+		field := fmt.Sprintf("ServiceLevels[%d]", i)
+		if serviceLevel.Percentile <= 0 || serviceLevel.Percentile > 100 {
+			v.addProblem(field+".Percentile", "%v is not between 0 and 100", serviceLevel.Percentile)
+		}
+		if serviceLevel.Days <= 0 {
+			v.addProblem(field+".Days", "%v is not a positive number of days", serviceLevel.Days)
+		}
+	}
+}
+
+func (v *configValidator) validateCustomFields() {
+	for i, customField := range BoardCfg.CustomFields {
+		field := fmt.Sprintf("CustomFields[%d]", i)
+		if customField.Id == "" && customField.Name == "" {
+			v.addProblem(field, "needs an Id or a Name")
+		}
+		if !containsIgnoringCase(customFieldTypes, customField.Type) {
+			v.addProblem(field+".Type", "%q is not one of %v", customField.Type, strings.Join(customFieldTypes, ", "))
+		}
+	}
+}
+
+func (v *configValidator) validateCalendar() {
+	calendar := BoardCfg.Calendar
+	for i, day := range calendar.WorkingDays {
+		if _, ok := parseWeekday(day); !ok {
+			v.addProblem(fmt.Sprintf("Calendar.WorkingDays[%d]", i), "%v is not a weekday", day)
+		}
+	}
+	for i, holiday := range calendar.Holidays {
+		if _, _, ok := parseHoliday(holiday); !ok {
+			v.addProblem(fmt.Sprintf("Calendar.Holidays[%d]", i), "%v is not dd/mm/yyyy or dd/mm/yyyy-dd/mm/yyyy", holiday)
+		}
+	}
+	if calendar.HolidaysFile != "" {
+		v.validateFile("Calendar.HolidaysFile", calendar.HolidaysFile)
+	}
+	if calendar.WorkingHours != nil {
+		if _, _, ok := parseWorkingHours(*calendar.WorkingHours); !ok {
+			v.addProblem("Calendar.WorkingHours", "%v-%v is not a start and end in hh:mm format",
+				calendar.WorkingHours.Start, calendar.WorkingHours.End)
+		}
+		if _, err := time.LoadLocation(calendar.WorkingHours.TimeZone); err != nil {
+			v.addProblem("Calendar.WorkingHours.TimeZone", "%v", err)
+		}
+	}
+}
+
+func (v *configValidator) validateConnection() {
+	if BoardCfg.TLS.CAFile != "" {
+		v.validateFile("TLS.CAFile", BoardCfg.TLS.CAFile)
+	}
+	if (BoardCfg.TLS.CertFile == "") != (BoardCfg.TLS.KeyFile == "") {
+		v.addProblem("TLS", "client certificates need both CertFile and KeyFile")
+	}
+	if BoardCfg.TLS.CertFile != "" {
+		v.validateFile("TLS.CertFile", BoardCfg.TLS.CertFile)
+	}
+	if BoardCfg.TLS.KeyFile != "" {
+		v.validateFile("TLS.KeyFile", BoardCfg.TLS.KeyFile)
+	}
+	if BoardCfg.Proxy != "" {
+		if _, err := url.Parse(BoardCfg.Proxy); err != nil {
+			v.addProblem("Proxy", "%v", err)
+		}
+	}
+}
+
+func (v *configValidator) validateFile(field string, path string) {
+	if _, err := os.Stat(getConfigRelativePath(path)); err != nil {
+		v.addProblem(field, "%v", err)
+	}
+}
+
+// Validates the board chosen with --board, or every board of the config file, and exits with an error
+// status when problems are found
+func printValidation() {
+	boards := []string{CLParameters.Board}
+	if CLParameters.Board == "" {
+		decodeConfigFile()
+		if len(BoardCfg.Boards) > 0 {
+			boards = getBoardNames()
+		}
+	}
+
+	var invalid bool
+	for _, board := range boards {
+		readBoardCfg(board)
+		if BoardCfg.Board != "" {
+			title("Board %v\n", BoardCfg.Board)
+		} else {
+			title("Config file %v\n", configPath)
+		}
+		problems := validateBoardCfg()
+		for _, problem := range problems {
+			warn("%v\n", problem)
+		}
+		if len(problems) == 0 {
+			info("Valid\n")
+		}
+		invalid = invalid || len(problems) > 0
+	}
+	if invalid {
+		os.Exit(1)
+	}
+}
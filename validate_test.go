@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateStatusesById(t *testing.T) {
+	BoardCfg = BoardConfig{
+		OpenStatus: []string{"Open"},
+		WipStatus:  []string{"In Progress", "Review"},
+		DoneStatus: []string{"Done", "3"},
+	}
+	knownStatusesById, knownStatusesByName = make(map[string]StatusInfo), make(map[string]StatusInfo)
+	defer func() {
+		knownStatusesById, knownStatusesByName = make(map[string]StatusInfo), make(map[string]StatusInfo)
+	}()
+
+	v := configValidator{}
+	v.validateStatuses()
+	if len(v.problems) != 0 {
+		t.Errorf("problems before the statuses are known = %v, want none", v.problems)
+	}
+
+	addKnownStatus("3", "In Progress", "")
+	addKnownStatus("4", "REVIEW", "")
+	v = configValidator{}
+	v.validateStatuses()
+	want := []string{"DoneStatus[1]: status 3 is already mapped in WipStatus[0]"}
+	if !reflect.DeepEqual(v.problems, want) {
+		t.Errorf("problems = %v, want %v", v.problems, want)
+	}
+}